@@ -3,6 +3,17 @@ package accesstypes
 
 import "context"
 
+// Enforcer checks whether a user holds a permission on a set of resources.
+//
+// RequireResources reports ok when the user holds perms on every resource in resources, in domain
+// or in GlobalDomain. A tagged resource (i.e. Resource.tag) is checked on its own and is neither
+// implied by nor implies its base resource. missing lists the resources the user does not hold
+// perms on, in the order they were requested. An empty resources list is always ok.
+// Implementations supporting deny entries apply deny-overrides: a deny in domain or in GlobalDomain
+// overrides any allow, and a deny on a resource also denies its tags (see RolePolicy).
+// When err is non-nil, ok is false and missing is nil.
+//
+// The accesstypestest package provides a conformance suite for implementations.
 type Enforcer interface {
 	RequireResources(ctx context.Context, user User, domain Domain, perms Permission, resources ...Resource) (ok bool, missing []Resource, err error)
 }
@@ -0,0 +1,234 @@
+package accesstypestest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+)
+
+// Conformance is a reusable test suite asserting the accesstypes.Enforcer contract.
+type Conformance struct {
+	// New returns an Enforcer with no grants. Required.
+	New func(t *testing.T) accesstypes.Enforcer
+	// Grant gives user perm on resources in domain. Required.
+	Grant func(t *testing.T, e accesstypes.Enforcer, user accesstypes.User, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource)
+	// Deny explicitly denies user perm on resources in domain.
+	// The deny-overrides checks are skipped when Deny is nil.
+	Deny func(t *testing.T, e accesstypes.Enforcer, user accesstypes.User, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource)
+	// NewFailing returns an Enforcer whose backing policy store fails.
+	// The error propagation checks are skipped when NewFailing is nil.
+	NewFailing func(t *testing.T) accesstypes.Enforcer
+}
+
+type grant struct {
+	user      accesstypes.User
+	domain    accesstypes.Domain
+	perm      accesstypes.Permission
+	resources []accesstypes.Resource
+}
+
+// Run executes the conformance suite as subtests of t.
+func (c Conformance) Run(t *testing.T) {
+	t.Helper()
+
+	if c.New == nil || c.Grant == nil {
+		t.Fatal("Conformance.New and Conformance.Grant are required")
+	}
+
+	const (
+		user      accesstypes.User       = "conformanceUser"
+		otherUser accesstypes.User       = "otherConformanceUser"
+		domain    accesstypes.Domain     = "conformanceDomain"
+		other     accesstypes.Domain     = "otherConformanceDomain"
+		perm      accesstypes.Permission = accesstypes.Read
+	)
+
+	type args struct {
+		domain    accesstypes.Domain
+		resources []accesstypes.Resource
+	}
+	tests := []struct {
+		name        string
+		grants      []grant
+		denies      []grant
+		args        args
+		wantOK      bool
+		wantMissing []accesstypes.Resource
+	}{
+		{
+			name:   "granted resource",
+			grants: []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			args:   args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantOK: true,
+		},
+		{
+			name:        "no grants",
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:   "empty resources list",
+			args:   args{domain: domain},
+			wantOK: true,
+		},
+		{
+			name:        "missing keeps request order",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Bravo"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Charlie", "Bravo", "Alpha"}},
+			wantMissing: []accesstypes.Resource{"Charlie", "Alpha"},
+		},
+		{
+			name:        "other permission",
+			grants:      []grant{{user, domain, accesstypes.Update, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:        "other user",
+			grants:      []grant{{otherUser, domain, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:        "other domain",
+			grants:      []grant{{user, other, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:   "global domain fallback",
+			grants: []grant{{user, accesstypes.GlobalDomain, perm, []accesstypes.Resource{"Users"}}},
+			args:   args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantOK: true,
+		},
+		{
+			name:        "domain grant does not apply globally",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: accesstypes.GlobalDomain, resources: []accesstypes.Resource{"Users"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:   "mixed domain and global grants",
+			grants: []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}, {user, accesstypes.GlobalDomain, perm, []accesstypes.Resource{"Orders"}}},
+			args:   args{domain: domain, resources: []accesstypes.Resource{"Users", "Orders"}},
+			wantOK: true,
+		},
+		{
+			name:        "tagged resource is not implied by base resource",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users", accesstypes.Resource("Users").ResourceWithTag("ssn")}},
+			wantMissing: []accesstypes.Resource{"Users.ssn"},
+		},
+		{
+			name:        "base resource is not implied by tagged resource",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users.ssn"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users", "Users.ssn"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:   "tagged resource granted",
+			grants: []grant{{user, domain, perm, []accesstypes.Resource{"Users", "Users.ssn"}}},
+			args:   args{domain: domain, resources: []accesstypes.Resource{"Users", "Users.ssn"}},
+			wantOK: true,
+		},
+		{
+			name:        "deny overrides grant",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users", "Orders"}}},
+			denies:      []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users", "Orders"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:        "global deny overrides domain grant",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			denies:      []grant{{user, accesstypes.GlobalDomain, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantMissing: []accesstypes.Resource{"Users"},
+		},
+		{
+			name:        "deny on resource denies tagged resource",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users.ssn"}}},
+			denies:      []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users.ssn"}},
+			wantMissing: []accesstypes.Resource{"Users.ssn"},
+		},
+		{
+			name:        "deny on tagged resource does not deny resource",
+			grants:      []grant{{user, domain, perm, []accesstypes.Resource{"Users", "Users.ssn"}}},
+			denies:      []grant{{user, domain, perm, []accesstypes.Resource{"Users.ssn"}}},
+			args:        args{domain: domain, resources: []accesstypes.Resource{"Users", "Users.ssn"}},
+			wantMissing: []accesstypes.Resource{"Users.ssn"},
+		},
+		{
+			name:   "deny in other domain does not apply",
+			grants: []grant{{user, domain, perm, []accesstypes.Resource{"Users"}}},
+			denies: []grant{{user, other, perm, []accesstypes.Resource{"Users"}}},
+			args:   args{domain: domain, resources: []accesstypes.Resource{"Users"}},
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.denies) > 0 && c.Deny == nil {
+				t.Skip("Conformance.Deny not provided")
+			}
+
+			e := c.New(t)
+			for _, g := range tt.grants {
+				c.Grant(t, e, g.user, g.domain, g.perm, g.resources...)
+			}
+			for _, g := range tt.denies {
+				c.Deny(t, e, g.user, g.domain, g.perm, g.resources...)
+			}
+
+			ok, missing, err := e.RequireResources(context.Background(), user, tt.args.domain, perm, tt.args.resources...)
+			if err != nil {
+				t.Fatalf("RequireResources() unexpected error = %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("RequireResources() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !slices.Equal(missing, tt.wantMissing) {
+				t.Errorf("RequireResources() missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+
+	t.Run("delete roles of unknown service account", func(t *testing.T) {
+		assigner, ok := c.New(t).(accesstypes.ServiceAccountRoleAssigner)
+		if !ok {
+			t.Skip("Enforcer does not implement accesstypes.ServiceAccountRoleAssigner")
+		}
+
+		if err := assigner.DeleteServiceAccountRoles(context.Background(), domain, "conformanceServiceAccount", "Reader"); err != nil {
+			t.Fatalf("DeleteServiceAccountRoles() unexpected error = %v", err)
+		}
+
+		roles, err := assigner.ServiceAccountRoles(context.Background(), "conformanceServiceAccount")
+		if err != nil {
+			t.Fatalf("ServiceAccountRoles() unexpected error = %v", err)
+		}
+		if len(roles) != 0 {
+			t.Errorf("ServiceAccountRoles() = %v, want none", roles)
+		}
+	})
+
+	t.Run("error propagation", func(t *testing.T) {
+		if c.NewFailing == nil {
+			t.Skip("Conformance.NewFailing not provided")
+		}
+
+		ok, missing, err := c.NewFailing(t).RequireResources(context.Background(), user, domain, perm, "Users")
+		if err == nil {
+			t.Fatal("RequireResources() expected error, got nil")
+		}
+		if ok {
+			t.Errorf("RequireResources() ok = %v when returning an error, want false", ok)
+		}
+		if missing != nil {
+			t.Errorf("RequireResources() missing = %v when returning an error, want nil", missing)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+// package accesstypestest provides a conformance suite and an in-memory fake for accesstypes.Enforcer implementations.
+package accesstypestest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/cccteam/ccc/accesstypes"
+)
+
+// Enforcer is an in-memory accesstypes.Enforcer, accesstypes.ServiceAccountEnforcer and accesstypes.ServiceAccountRoleAssigner
+// that follows the documented Enforcer contract. It is intended as a real-behaviour alternative to the generated mock in tests.
+//
+// Principals hold the permissions granted to them directly along with the permissions of the roles assigned to them,
+// resolved with the Policy set with SetPolicy.
+type Enforcer struct {
+	mu     sync.RWMutex
+	grants map[accesstypes.Principal]accesstypes.UserPermissionCollection
+	denies map[accesstypes.Principal]accesstypes.UserPermissionCollection
+	roles  map[accesstypes.Principal]accesstypes.RoleCollection
+	policy accesstypes.Policy
+	err    error
+}
+
+// NewEnforcer returns an Enforcer with no grants.
+func NewEnforcer() *Enforcer {
+	return &Enforcer{
+		grants: make(map[accesstypes.Principal]accesstypes.UserPermissionCollection),
+		denies: make(map[accesstypes.Principal]accesstypes.UserPermissionCollection),
+		roles:  make(map[accesstypes.Principal]accesstypes.RoleCollection),
+	}
+}
+
+// SetPolicy sets the policy used to resolve the permissions of assigned roles.
+func (e *Enforcer) SetPolicy(policy accesstypes.Policy) *Enforcer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.policy = policy
+
+	return e
+}
+
+// AssignRoles assigns roles to principal in domain.
+func (e *Enforcer) AssignRoles(principal accesstypes.Principal, domain accesstypes.Domain, roles ...accesstypes.Role) *Enforcer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.roles[principal] == nil {
+		e.roles[principal] = make(accesstypes.RoleCollection)
+	}
+	for _, role := range roles {
+		if !slices.Contains(e.roles[principal][domain], role) {
+			e.roles[principal][domain] = append(e.roles[principal][domain], role)
+		}
+	}
+
+	return e
+}
+
+func (e *Enforcer) AddServiceAccountRoles(_ context.Context, domain accesstypes.Domain, serviceAccount accesstypes.ServiceAccount, roles ...accesstypes.Role) error {
+	e.AssignRoles(serviceAccount, domain, roles...)
+
+	return nil
+}
+
+func (e *Enforcer) DeleteServiceAccountRoles(_ context.Context, domain accesstypes.Domain, serviceAccount accesstypes.ServiceAccount, roles ...accesstypes.Role) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.roles[serviceAccount] == nil {
+		return nil
+	}
+
+	e.roles[serviceAccount][domain] = slices.DeleteFunc(e.roles[serviceAccount][domain], func(role accesstypes.Role) bool {
+		return slices.Contains(roles, role)
+	})
+
+	return nil
+}
+
+func (e *Enforcer) ServiceAccountRoles(_ context.Context, serviceAccount accesstypes.ServiceAccount, domains ...accesstypes.Domain) (accesstypes.RoleCollection, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	roles := make(accesstypes.RoleCollection)
+	for domain, assigned := range e.roles[serviceAccount] {
+		if len(assigned) > 0 && (len(domains) == 0 || slices.Contains(domains, domain)) {
+			roles[domain] = slices.Clone(assigned)
+		}
+	}
+
+	return roles, nil
+}
+
+// Grant gives principal perm on resources in domain.
+func (e *Enforcer) Grant(principal accesstypes.Principal, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource) *Enforcer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	add(e.grants, principal, domain, perm, resources)
+
+	return e
+}
+
+// Deny explicitly denies principal perm on resources in domain. A deny overrides any grant.
+func (e *Enforcer) Deny(principal accesstypes.Principal, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource) *Enforcer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	add(e.denies, principal, domain, perm, resources)
+
+	return e
+}
+
+// SetError causes every subsequent call to RequireResources to fail with err. A nil err clears the failure.
+func (e *Enforcer) SetError(err error) *Enforcer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = err
+
+	return e
+}
+
+func (e *Enforcer) RequireResources(_ context.Context, user accesstypes.User, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource) (bool, []accesstypes.Resource, error) {
+	return e.requireResources(user, domain, perm, resources)
+}
+
+func (e *Enforcer) RequireServiceAccountResources(_ context.Context, serviceAccount accesstypes.ServiceAccount, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource) (bool, []accesstypes.Resource, error) {
+	return e.requireResources(serviceAccount, domain, perm, resources)
+}
+
+func (e *Enforcer) requireResources(principal accesstypes.Principal, domain accesstypes.Domain, perm accesstypes.Permission, resources []accesstypes.Resource) (bool, []accesstypes.Resource, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.err != nil {
+		return false, nil, e.err
+	}
+
+	var missing []accesstypes.Resource
+	for _, res := range resources {
+		explanation := e.policy.Explain(e.roles[principal], domain, perm, res)
+		roleDenied := slices.ContainsFunc(explanation.Entries, func(entry accesstypes.PolicyEntry) bool {
+			return entry.Effect == accesstypes.DenyEffect
+		})
+		if e.isDenied(principal, domain, perm, res) || roleDenied || (!e.isGranted(principal, domain, perm, res) && !explanation.Allowed) {
+			missing = append(missing, res)
+		}
+	}
+
+	return len(missing) == 0, missing, nil
+}
+
+func (e *Enforcer) isGranted(principal accesstypes.Principal, domain accesstypes.Domain, perm accesstypes.Permission, res accesstypes.Resource) bool {
+	return slices.Contains(e.grants[principal][domain][res], perm) || slices.Contains(e.grants[principal][accesstypes.GlobalDomain][res], perm)
+}
+
+func (e *Enforcer) isDenied(principal accesstypes.Principal, domain accesstypes.Domain, perm accesstypes.Permission, res accesstypes.Resource) bool {
+	base, tag := res.ResourceAndTag()
+	for _, d := range []accesstypes.Domain{domain, accesstypes.GlobalDomain} {
+		if slices.Contains(e.denies[principal][d][res], perm) {
+			return true
+		}
+		if tag != "" && slices.Contains(e.denies[principal][d][base], perm) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func add(store map[accesstypes.Principal]accesstypes.UserPermissionCollection, principal accesstypes.Principal, domain accesstypes.Domain, perm accesstypes.Permission, resources []accesstypes.Resource) {
+	if store[principal] == nil {
+		store[principal] = make(accesstypes.UserPermissionCollection)
+	}
+	if store[principal][domain] == nil {
+		store[principal][domain] = make(map[accesstypes.Resource][]accesstypes.Permission)
+	}
+	for _, res := range resources {
+		store[principal][domain][res] = append(store[principal][domain][res], perm)
+	}
+}
@@ -0,0 +1,98 @@
+package accesstypestest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+)
+
+func TestEnforcer_Conformance(t *testing.T) {
+	t.Parallel()
+
+	Conformance{
+		New: func(*testing.T) accesstypes.Enforcer {
+			return NewEnforcer()
+		},
+		Grant: func(t *testing.T, e accesstypes.Enforcer, user accesstypes.User, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource) {
+			e.(*Enforcer).Grant(user, domain, perm, resources...)
+		},
+		Deny: func(t *testing.T, e accesstypes.Enforcer, user accesstypes.User, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource) {
+			e.(*Enforcer).Deny(user, domain, perm, resources...)
+		},
+		NewFailing: func(*testing.T) accesstypes.Enforcer {
+			return NewEnforcer().SetError(errors.New("policy store unavailable"))
+		},
+	}.Run(t)
+}
+
+func TestEnforcer_RequireServiceAccountResources(t *testing.T) {
+	t.Parallel()
+
+	e := NewEnforcer().
+		Grant(accesstypes.ServiceAccount("batch"), accesstypes.GlobalDomain, accesstypes.Read, "Users").
+		Grant(accesstypes.User("batch"), accesstypes.GlobalDomain, accesstypes.Update, "Users")
+
+	ok, missing, err := e.RequireServiceAccountResources(context.Background(), "batch", "tenant", accesstypes.Read, "Users")
+	if err != nil || !ok || len(missing) != 0 {
+		t.Errorf("RequireServiceAccountResources() = (%v, %v, %v), want (true, [], nil)", ok, missing, err)
+	}
+
+	// Grants to a user with the same name must not leak to the service account
+	ok, missing, err = e.RequireServiceAccountResources(context.Background(), "batch", "tenant", accesstypes.Update, "Users")
+	if err != nil || ok || len(missing) != 1 {
+		t.Errorf("RequireServiceAccountResources() = (%v, %v, %v), want (false, [Users], nil)", ok, missing, err)
+	}
+}
+
+func TestEnforcer_ServiceAccountRoles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	e := NewEnforcer().SetPolicy(accesstypes.Policy{
+		accesstypes.GlobalDomain: {
+			"Reader": {Allow: accesstypes.RolePermissionCollection{accesstypes.Read: {"Users", "Orders"}}},
+		},
+		"tenant": {
+			"Restricted": {Deny: accesstypes.RolePermissionCollection{accesstypes.Read: {"Orders"}}},
+		},
+	})
+
+	if err := e.AddServiceAccountRoles(ctx, accesstypes.GlobalDomain, "batch", "Reader"); err != nil {
+		t.Fatalf("AddServiceAccountRoles() error = %v", err)
+	}
+	if err := e.AddServiceAccountRoles(ctx, "tenant", "batch", "Restricted"); err != nil {
+		t.Fatalf("AddServiceAccountRoles() error = %v", err)
+	}
+
+	roles, err := e.ServiceAccountRoles(ctx, "batch", "tenant")
+	if err != nil {
+		t.Fatalf("ServiceAccountRoles() error = %v", err)
+	}
+	if want := (accesstypes.RoleCollection{"tenant": {"Restricted"}}); !reflect.DeepEqual(roles, want) {
+		t.Errorf("ServiceAccountRoles() = %v, want %v", roles, want)
+	}
+
+	ok, missing, err := e.RequireServiceAccountResources(ctx, "batch", "tenant", accesstypes.Read, "Users", "Orders")
+	if err != nil || ok || !slices.Equal(missing, []accesstypes.Resource{"Orders"}) {
+		t.Errorf("RequireServiceAccountResources() = (%v, %v, %v), want (false, [Orders], nil)", ok, missing, err)
+	}
+
+	if err := e.DeleteServiceAccountRoles(ctx, "tenant", "batch", "Restricted"); err != nil {
+		t.Fatalf("DeleteServiceAccountRoles() error = %v", err)
+	}
+
+	ok, missing, err = e.RequireServiceAccountResources(ctx, "batch", "tenant", accesstypes.Read, "Users", "Orders")
+	if err != nil || !ok || len(missing) != 0 {
+		t.Errorf("RequireServiceAccountResources() = (%v, %v, %v), want (true, [], nil)", ok, missing, err)
+	}
+
+	// Roles assigned to the service account must not apply to a user with the same name
+	ok, _, err = e.RequireResources(ctx, "batch", "tenant", accesstypes.Read, "Users")
+	if err != nil || ok {
+		t.Errorf("RequireResources() = (%v, %v), want (false, nil)", ok, err)
+	}
+}
@@ -30,6 +30,10 @@ type (
 type ResolvedPermissions struct {
 	Resources ResolvedResourcePermissions
 	Tags      ResolvedTagPermissions
+	// DeniedResources and DeniedTags hold the explicit deny entries that were applied.
+	// A denied permission is always false in Resources and Tags.
+	DeniedResources ResolvedResourcePermissions
+	DeniedTags      ResolvedTagPermissions
 }
 
 const (
@@ -37,9 +41,23 @@ const (
 	DomainPermissionScope PermissionScope = "domain"
 )
 
+// PermissionKind classifies a permission by how it interacts with a resource.
+type PermissionKind string
+
+const (
+	// ViewingPermissionKind permissions read data (e.g. Read, List)
+	ViewingPermissionKind PermissionKind = "viewing"
+	// MutatingPermissionKind permissions modify data (e.g. Create, Update, Delete)
+	MutatingPermissionKind PermissionKind = "mutating"
+	// ActionPermissionKind permissions perform an operation on a resource (e.g. Approve, Export)
+	// and can be combined with viewing or mutating permissions in the same struct
+	ActionPermissionKind PermissionKind = "action"
+)
+
 type PermissionDetail struct {
 	Description string
 	Scope       PermissionScope
+	Kind        PermissionKind
 }
 
 func UnmarshalPermission(permission string) Permission {
@@ -0,0 +1,88 @@
+package accesstypes
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+)
+
+var permissionRegistry = struct {
+	mu      sync.RWMutex
+	details map[Permission]PermissionDetail
+}{
+	details: map[Permission]PermissionDetail{
+		Create: {Description: "Create a new resource", Kind: MutatingPermissionKind},
+		Read:   {Description: "Read a resource", Kind: ViewingPermissionKind},
+		List:   {Description: "List resources", Kind: ViewingPermissionKind},
+		Update: {Description: "Update an existing resource", Kind: MutatingPermissionKind},
+		Delete: {Description: "Delete an existing resource", Kind: MutatingPermissionKind},
+	},
+}
+
+// RegisterPermission declares a custom permission along with its kind and description.
+// Registering the same permission again with an identical detail is a no-op.
+//
+// Permissions must be registered before they are used to create a resource set, which rejects unregistered
+// permissions. Register them in package-level vars or init functions of the package declaring the permission.
+func RegisterPermission(perm Permission, detail PermissionDetail) error {
+	if perm == NullPermission || !perm.isValid() {
+		return fmt.Errorf("invalid permission %q", perm)
+	}
+
+	switch detail.Kind {
+	case ViewingPermissionKind, MutatingPermissionKind, ActionPermissionKind:
+	default:
+		return fmt.Errorf("invalid kind %q for permission %q", detail.Kind, perm)
+	}
+
+	permissionRegistry.mu.Lock()
+	defer permissionRegistry.mu.Unlock()
+
+	if existing, ok := permissionRegistry.details[perm]; ok {
+		if existing == detail {
+			return nil
+		}
+
+		return fmt.Errorf("permission %q is already registered", perm)
+	}
+
+	permissionRegistry.details[perm] = detail
+
+	return nil
+}
+
+// MustRegisterPermission is like RegisterPermission but panics on error.
+func MustRegisterPermission(perm Permission, detail PermissionDetail) Permission {
+	if err := RegisterPermission(perm, detail); err != nil {
+		panic(err)
+	}
+
+	return perm
+}
+
+// LookupPermission returns the detail registered for perm.
+func LookupPermission(perm Permission) (PermissionDetail, bool) {
+	permissionRegistry.mu.RLock()
+	defer permissionRegistry.mu.RUnlock()
+
+	detail, ok := permissionRegistry.details[perm]
+
+	return detail, ok
+}
+
+// RegisteredPermissions returns the details of every built-in and registered permission.
+func RegisteredPermissions() map[Permission]PermissionDetail {
+	permissionRegistry.mu.RLock()
+	defer permissionRegistry.mu.RUnlock()
+
+	return maps.Clone(permissionRegistry.details)
+}
+
+// Kind returns the kind of p. Permissions that have not been registered are treated as viewing permissions.
+func (p Permission) Kind() PermissionKind {
+	if detail, ok := LookupPermission(p); ok {
+		return detail.Kind
+	}
+
+	return ViewingPermissionKind
+}
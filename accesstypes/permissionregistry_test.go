@@ -0,0 +1,92 @@
+package accesstypes
+
+import (
+	"testing"
+)
+
+func TestRegisterPermission(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		perm     Permission
+		detail   PermissionDetail
+		wantKind PermissionKind
+		wantErr  bool
+	}{
+		{
+			name:     "action",
+			perm:     "TestRegisterApprove",
+			detail:   PermissionDetail{Description: "Approve a request", Kind: ActionPermissionKind},
+			wantKind: ActionPermissionKind,
+		},
+		{
+			name:     "mutating",
+			perm:     "TestRegisterArchive",
+			detail:   PermissionDetail{Description: "Archive a resource", Kind: MutatingPermissionKind},
+			wantKind: MutatingPermissionKind,
+		},
+		{
+			name:     "redeclare built-in",
+			perm:     Read,
+			detail:   PermissionDetail{Kind: MutatingPermissionKind},
+			wantKind: ViewingPermissionKind,
+			wantErr:  true,
+		},
+		{
+			name:    "invalid kind",
+			perm:    "TestRegisterInvalidKind",
+			detail:  PermissionDetail{Kind: "other"},
+			wantErr: true,
+		},
+		{
+			name:    "null permission",
+			perm:    NullPermission,
+			detail:  PermissionDetail{Kind: ActionPermissionKind},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := RegisterPermission(tt.perm, tt.detail); (err != nil) != tt.wantErr {
+				t.Fatalf("RegisterPermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantKind != "" {
+				if got := tt.perm.Kind(); got != tt.wantKind {
+					t.Errorf("Permission.Kind() = %v, want %v", got, tt.wantKind)
+				}
+			}
+			if !tt.wantErr {
+				if err := RegisterPermission(tt.perm, tt.detail); err != nil {
+					t.Errorf("RegisterPermission() repeat registration error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPermission_Kind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		p    Permission
+		want PermissionKind
+	}{
+		{name: "Create", p: Create, want: MutatingPermissionKind},
+		{name: "Update", p: Update, want: MutatingPermissionKind},
+		{name: "Delete", p: Delete, want: MutatingPermissionKind},
+		{name: "Read", p: Read, want: ViewingPermissionKind},
+		{name: "List", p: List, want: ViewingPermissionKind},
+		{name: "unregistered", p: "TestKindUnregistered", want: ViewingPermissionKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.p.Kind(); got != tt.want {
+				t.Errorf("Permission.Kind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package accesstypes
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Effect is the outcome of a policy entry.
+type Effect string
+
+const (
+	AllowEffect Effect = "allow"
+	DenyEffect  Effect = "deny"
+)
+
+// RolePolicy holds the permissions a role is allowed and explicitly denied.
+//
+// A deny on a resource also denies every tag of that resource (i.e. a deny on Users denies Users.ssn),
+// while a deny on a tagged resource only applies to that tag.
+type RolePolicy struct {
+	Allow RolePermissionCollection `json:"allow,omitempty"`
+	Deny  RolePermissionCollection `json:"deny,omitempty"`
+}
+
+// Denies reports whether r explicitly denies perm on res, either directly or through a deny on its base resource.
+func (r RolePolicy) Denies(perm Permission, res Resource) bool {
+	base, tag := res.ResourceAndTag()
+	for _, denied := range r.Deny[perm] {
+		if denied == res || (tag != "" && denied == base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Policy holds the RolePolicy for each role in each domain.
+type Policy map[Domain]map[Role]RolePolicy
+
+// PolicyEntry is a single allow or deny entry in a Policy.
+type PolicyEntry struct {
+	Domain     Domain
+	Role       Role
+	Permission Permission
+	Resource   Resource
+	Effect     Effect
+}
+
+func (e PolicyEntry) String() string {
+	return fmt.Sprintf("%s %s on %s via role %s in domain %s", e.Effect, e.Permission, e.Resource, e.Role, e.Domain)
+}
+
+// Explanation describes how a decision was reached.
+type Explanation struct {
+	Allowed bool
+	// Entries are the policy entries that matched the request, allow entries first
+	Entries []PolicyEntry
+}
+
+func (e Explanation) String() string {
+	var b strings.Builder
+	if e.Allowed {
+		b.WriteString("allowed")
+	} else {
+		b.WriteString("denied")
+	}
+
+	if len(e.Entries) == 0 {
+		b.WriteString(": no matching policy entries")
+
+		return b.String()
+	}
+
+	for _, entry := range e.Entries {
+		b.WriteString("\n  - ")
+		b.WriteString(entry.String())
+	}
+
+	return b.String()
+}
+
+// Resolve returns the permissions held by a user assigned roles.
+//
+// Roles assigned in GlobalDomain apply in every domain. Deny-overrides semantics are applied:
+// an explicit deny from any role, in the domain or in GlobalDomain, overrides every allow.
+// A denied permission is false in Resources and Tags and is recorded in DeniedResources and DeniedTags.
+func (p Policy) Resolve(roles RoleCollection) ResolvedPermissions {
+	resolved := ResolvedPermissions{
+		Resources:       make(ResolvedResourcePermissions),
+		Tags:            make(ResolvedTagPermissions),
+		DeniedResources: make(ResolvedResourcePermissions),
+		DeniedTags:      make(ResolvedTagPermissions),
+	}
+
+	for domain := range roles {
+		entries := p.entries(roles, domain)
+		for _, entry := range entries {
+			denied := entry.Effect == DenyEffect || isDenied(entries, entry.Permission, entry.Resource)
+			if entry.Effect == DenyEffect {
+				setResolved(resolved.DeniedResources, resolved.DeniedTags, domain, entry.Resource, entry.Permission, true)
+			}
+			setResolved(resolved.Resources, resolved.Tags, domain, entry.Resource, entry.Permission, !denied)
+		}
+	}
+
+	return resolved
+}
+
+// Explain reports whether a user assigned roles holds perm on res in domain, along with
+// the policy entries that led to the decision.
+func (p Policy) Explain(roles RoleCollection, domain Domain, perm Permission, res Resource) Explanation {
+	base, tag := res.ResourceAndTag()
+
+	var explanation Explanation
+	var allowed bool
+	for _, entry := range p.entries(roles, domain) {
+		if entry.Permission != perm {
+			continue
+		}
+
+		switch entry.Effect {
+		case AllowEffect:
+			if entry.Resource == res {
+				allowed = true
+				explanation.Entries = append(explanation.Entries, entry)
+			}
+		case DenyEffect:
+			if entry.Resource == res || (tag != "" && entry.Resource == base) {
+				explanation.Entries = append(explanation.Entries, entry)
+			}
+		}
+	}
+
+	slices.SortFunc(explanation.Entries, func(a, b PolicyEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.Effect, b.Effect),
+			cmp.Compare(a.Domain, b.Domain),
+			cmp.Compare(a.Role, b.Role),
+			cmp.Compare(a.Resource, b.Resource),
+		)
+	})
+	explanation.Allowed = allowed && !isDenied(explanation.Entries, perm, res)
+
+	return explanation
+}
+
+// entries returns the policy entries that apply in domain for roles, including roles assigned in GlobalDomain.
+func (p Policy) entries(roles RoleCollection, domain Domain) []PolicyEntry {
+	domains := []Domain{domain}
+	if domain != GlobalDomain {
+		domains = append(domains, GlobalDomain)
+	}
+
+	var entries []PolicyEntry
+	for _, d := range domains {
+		for _, role := range roles[d] {
+			rolePolicy := p[d][role]
+			for effect, perms := range map[Effect]RolePermissionCollection{AllowEffect: rolePolicy.Allow, DenyEffect: rolePolicy.Deny} {
+				for perm, resources := range perms {
+					for _, res := range resources {
+						entries = append(entries, PolicyEntry{Domain: d, Role: role, Permission: perm, Resource: res, Effect: effect})
+					}
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+func isDenied(entries []PolicyEntry, perm Permission, res Resource) bool {
+	base, tag := res.ResourceAndTag()
+	for _, entry := range entries {
+		if entry.Effect != DenyEffect || entry.Permission != perm {
+			continue
+		}
+		if entry.Resource == res || (tag != "" && entry.Resource == base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setResolved(resources ResolvedResourcePermissions, tags ResolvedTagPermissions, domain Domain, res Resource, perm Permission, value bool) {
+	base, tag := res.ResourceAndTag()
+	if tag == "" {
+		if resources[domain] == nil {
+			resources[domain] = make(map[Resource]map[Permission]bool)
+		}
+		if resources[domain][res] == nil {
+			resources[domain][res] = make(map[Permission]bool)
+		}
+		resources[domain][res][perm] = value
+
+		return
+	}
+
+	if tags[domain] == nil {
+		tags[domain] = make(map[Resource]map[Tag]map[Permission]bool)
+	}
+	if tags[domain][base] == nil {
+		tags[domain][base] = make(map[Tag]map[Permission]bool)
+	}
+	if tags[domain][base][tag] == nil {
+		tags[domain][base][tag] = make(map[Permission]bool)
+	}
+	tags[domain][base][tag][perm] = value
+}
@@ -0,0 +1,165 @@
+package accesstypes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testPolicy() Policy {
+	return Policy{
+		GlobalDomain: {
+			"Auditor": {
+				Deny: RolePermissionCollection{Read: {"Users.ssn"}},
+			},
+		},
+		"tenant": {
+			"Support": {
+				Allow: RolePermissionCollection{Read: {"Users", "Users.ssn", "Users.email"}},
+			},
+			"Restricted": {
+				Deny: RolePermissionCollection{Read: {"Users"}},
+			},
+		},
+	}
+}
+
+func TestPolicy_Resolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		roles RoleCollection
+		want  ResolvedPermissions
+	}{
+		{
+			name:  "allow only",
+			roles: RoleCollection{"tenant": {"Support"}},
+			want: ResolvedPermissions{
+				Resources:       ResolvedResourcePermissions{"tenant": {"Users": {Read: true}}},
+				Tags:            ResolvedTagPermissions{"tenant": {"Users": {"ssn": {Read: true}, "email": {Read: true}}}},
+				DeniedResources: ResolvedResourcePermissions{},
+				DeniedTags:      ResolvedTagPermissions{},
+			},
+		},
+		{
+			name:  "global deny overrides domain allow on tag",
+			roles: RoleCollection{"tenant": {"Support"}, GlobalDomain: {"Auditor"}},
+			want: ResolvedPermissions{
+				Resources: ResolvedResourcePermissions{"tenant": {"Users": {Read: true}}},
+				Tags: ResolvedTagPermissions{
+					"tenant":     {"Users": {"ssn": {Read: false}, "email": {Read: true}}},
+					GlobalDomain: {"Users": {"ssn": {Read: false}}},
+				},
+				DeniedResources: ResolvedResourcePermissions{},
+				DeniedTags: ResolvedTagPermissions{
+					"tenant":     {"Users": {"ssn": {Read: true}}},
+					GlobalDomain: {"Users": {"ssn": {Read: true}}},
+				},
+			},
+		},
+		{
+			name:  "deny on resource overrides allow on resource and tags",
+			roles: RoleCollection{"tenant": {"Support", "Restricted"}},
+			want: ResolvedPermissions{
+				Resources:       ResolvedResourcePermissions{"tenant": {"Users": {Read: false}}},
+				Tags:            ResolvedTagPermissions{"tenant": {"Users": {"ssn": {Read: false}, "email": {Read: false}}}},
+				DeniedResources: ResolvedResourcePermissions{"tenant": {"Users": {Read: true}}},
+				DeniedTags:      ResolvedTagPermissions{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := testPolicy().Resolve(tt.roles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Policy.Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Explain(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		roles RoleCollection
+		res   Resource
+	}
+	tests := []struct {
+		name string
+		args args
+		want Explanation
+	}{
+		{
+			name: "allowed",
+			args: args{roles: RoleCollection{"tenant": {"Support"}}, res: "Users.email"},
+			want: Explanation{
+				Allowed: true,
+				Entries: []PolicyEntry{{Domain: "tenant", Role: "Support", Permission: Read, Resource: "Users.email", Effect: AllowEffect}},
+			},
+		},
+		{
+			name: "denied by global deny",
+			args: args{roles: RoleCollection{"tenant": {"Support"}, GlobalDomain: {"Auditor"}}, res: "Users.ssn"},
+			want: Explanation{
+				Entries: []PolicyEntry{
+					{Domain: "tenant", Role: "Support", Permission: Read, Resource: "Users.ssn", Effect: AllowEffect},
+					{Domain: GlobalDomain, Role: "Auditor", Permission: Read, Resource: "Users.ssn", Effect: DenyEffect},
+				},
+			},
+		},
+		{
+			name: "tag denied by deny on resource",
+			args: args{roles: RoleCollection{"tenant": {"Support", "Restricted"}}, res: "Users.email"},
+			want: Explanation{
+				Entries: []PolicyEntry{
+					{Domain: "tenant", Role: "Support", Permission: Read, Resource: "Users.email", Effect: AllowEffect},
+					{Domain: "tenant", Role: "Restricted", Permission: Read, Resource: "Users", Effect: DenyEffect},
+				},
+			},
+		},
+		{
+			name: "no entries",
+			args: args{roles: RoleCollection{"other": {"Support"}}, res: "Users"},
+			want: Explanation{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := testPolicy().Explain(tt.args.roles, "tenant", Read, tt.args.res); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Policy.Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolePolicy_Denies(t *testing.T) {
+	t.Parallel()
+
+	rolePolicy := RolePolicy{
+		Allow: RolePermissionCollection{Read: {"Users", "Users.ssn"}},
+		Deny:  RolePermissionCollection{Read: {"Users.ssn"}, Update: {"Users"}},
+	}
+
+	tests := []struct {
+		name string
+		perm Permission
+		res  Resource
+		want bool
+	}{
+		{name: "tag denied", perm: Read, res: "Users.ssn", want: true},
+		{name: "resource not denied by tag deny", perm: Read, res: "Users"},
+		{name: "resource denied", perm: Update, res: "Users", want: true},
+		{name: "tag denied by resource deny", perm: Update, res: "Users.email", want: true},
+		{name: "other permission", perm: Delete, res: "Users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := rolePolicy.Denies(tt.perm, tt.res); got != tt.want {
+				t.Errorf("RolePolicy.Denies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package accesstypes
+
+import (
+	"context"
+	"strings"
+)
+
+// Principal is a subject that can be assigned roles, either a User or a ServiceAccount.
+// A RoleCollection describes the roles assigned to any Principal.
+type Principal interface {
+	Marshal() string
+	isPrincipal()
+}
+
+// UnmarshalPrincipal returns the User or ServiceAccount encoded in principal.
+// Values without a service account prefix are treated as a User.
+func UnmarshalPrincipal(principal string) Principal {
+	if strings.HasPrefix(principal, serviceAccountPrefix) {
+		return UnmarshalServiceAccount(principal)
+	}
+
+	return UnmarshalUser(principal)
+}
+
+// ServiceAccountEnforcer checks whether a service account holds a permission on a set of resources.
+// It follows the same contract as Enforcer.
+type ServiceAccountEnforcer interface {
+	RequireServiceAccountResources(ctx context.Context, serviceAccount ServiceAccount, domain Domain, perms Permission, resources ...Resource) (ok bool, missing []Resource, err error)
+}
+
+// ServiceAccountRoleAssigner assigns roles to service accounts. Roles assigned in GlobalDomain apply in every domain.
+type ServiceAccountRoleAssigner interface {
+	AddServiceAccountRoles(ctx context.Context, domain Domain, serviceAccount ServiceAccount, roles ...Role) error
+	DeleteServiceAccountRoles(ctx context.Context, domain Domain, serviceAccount ServiceAccount, roles ...Role) error
+	// ServiceAccountRoles returns the roles assigned to serviceAccount in domains, or in every domain when none are given.
+	ServiceAccountRoles(ctx context.Context, serviceAccount ServiceAccount, domains ...Domain) (RoleCollection, error)
+}
@@ -0,0 +1,35 @@
+package accesstypes
+
+import (
+	"fmt"
+	"strings"
+)
+
+const serviceAccountPrefix = "serviceaccount:"
+
+// ServiceAccount represents a non-human principal (e.g. a batch job or an integration
+// authenticating with an API key) in the authorization system
+type ServiceAccount string
+
+func UnmarshalServiceAccount(serviceAccount string) ServiceAccount {
+	s := ServiceAccount(strings.TrimPrefix(serviceAccount, serviceAccountPrefix))
+	if !s.isValid() {
+		panic(fmt.Sprintf("invalid service account %q", serviceAccount))
+	}
+
+	return s
+}
+
+func (s ServiceAccount) Marshal() string {
+	if !s.isValid() {
+		panic(fmt.Sprintf("invalid service account %q, type can not contain prefix", string(s)))
+	}
+
+	return serviceAccountPrefix + string(s)
+}
+
+func (s ServiceAccount) isValid() bool {
+	return !strings.HasPrefix(string(s), serviceAccountPrefix)
+}
+
+func (ServiceAccount) isPrincipal() {}
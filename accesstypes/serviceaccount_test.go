@@ -0,0 +1,111 @@
+package accesstypes
+
+import (
+	"testing"
+)
+
+func TestServiceAccountFromStringAndBack(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		serviceAccount string
+	}
+	tests := []struct {
+		name string
+		args args
+		want ServiceAccount
+	}{
+		{
+			name: "BatchJob",
+			args: args{
+				serviceAccount: "serviceaccount:BatchJob",
+			},
+			want: "BatchJob",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := UnmarshalServiceAccount(tt.args.serviceAccount)
+			if got != tt.want {
+				t.Errorf("UnmarshalServiceAccount() = %v, want %v", got, tt.want)
+			}
+			if gotString := got.Marshal(); gotString != tt.args.serviceAccount {
+				t.Errorf("ServiceAccount.Marshal() = %v, want %v", gotString, tt.args.serviceAccount)
+			}
+		})
+	}
+}
+
+func TestServiceAccount_Marshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		serviceAccount ServiceAccount
+		want           string
+		wantPanic      bool
+	}{
+		{
+			name:           "Success",
+			serviceAccount: "MyServiceAccount",
+			want:           serviceAccountPrefix + "MyServiceAccount",
+		},
+		{
+			name:           "Panic",
+			serviceAccount: serviceAccountPrefix + "MyServiceAccount",
+			wantPanic:      true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				didPanic := recover()
+				if tt.wantPanic != (didPanic != nil) {
+					t.Errorf("ServiceAccount.Marshal() panic = %v, wantPanic %v", didPanic, tt.wantPanic)
+				}
+			}()
+			if got := tt.serviceAccount.Marshal(); got != tt.want {
+				t.Errorf("ServiceAccount.Marshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalPrincipal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		principal string
+		want      Principal
+	}{
+		{
+			name:      "user",
+			principal: "user:Administrator",
+			want:      User("Administrator"),
+		},
+		{
+			name:      "user without prefix",
+			principal: "Administrator",
+			want:      User("Administrator"),
+		},
+		{
+			name:      "service account",
+			principal: "serviceaccount:BatchJob",
+			want:      ServiceAccount("BatchJob"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := UnmarshalPrincipal(tt.principal); got != tt.want {
+				t.Errorf("UnmarshalPrincipal() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
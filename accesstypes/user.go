@@ -33,3 +33,5 @@ func (u User) Marshal() string {
 func (u User) isValid() bool {
 	return !strings.HasPrefix(string(u), userPrefix)
 }
+
+func (User) isPrincipal() {}
@@ -0,0 +1,247 @@
+// package accessreview builds access review reports from a resource.Collection snapshot and a role policy.
+package accessreview
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/ccc/resource"
+	"github.com/go-playground/errors/v5"
+)
+
+// Snapshot is a serializable copy of the resources and permissions registered in a resource.Collection.
+type Snapshot struct {
+	Resources []ResourceEntry `json:"resources"`
+}
+
+// ResourceEntry describes the permissions declared on a resource or a tagged resource (i.e. Resource.tag).
+type ResourceEntry struct {
+	Resource    accesstypes.Resource        `json:"resource"`
+	Scope       accesstypes.PermissionScope `json:"scope"`
+	Permissions []accesstypes.Permission    `json:"permissions"`
+	Immutable   bool                        `json:"immutable,omitempty"`
+}
+
+// NewSnapshot captures the resources registered in c.
+//
+// The Collection only records registrations when built with the collect_resource_permissions build tag,
+// or when created with resource.RuntimeCollection.
+func NewSnapshot(c *resource.Collection) *Snapshot {
+	entries := make(map[accesstypes.Resource]*ResourceEntry)
+	for permission, resources := range c.List() {
+		for _, res := range resources {
+			entry, ok := entries[res]
+			if !ok {
+				scope := c.Scope(res)
+				entry = &ResourceEntry{
+					Resource:  res,
+					Scope:     scope,
+					Immutable: c.IsResourceImmutable(scope, res),
+				}
+				entries[res] = entry
+			}
+			if !slices.Contains(entry.Permissions, permission) {
+				entry.Permissions = append(entry.Permissions, permission)
+			}
+		}
+	}
+
+	snapshot := &Snapshot{Resources: make([]ResourceEntry, 0, len(entries))}
+	for _, res := range slices.Sorted(maps.Keys(entries)) {
+		entry := entries[res]
+		slices.Sort(entry.Permissions)
+		snapshot.Resources = append(snapshot.Resources, *entry)
+	}
+
+	return snapshot
+}
+
+// LoadSnapshot decodes a JSON encoded Snapshot from r, sorting its resources and permissions
+// so that reports built from it are stable.
+func LoadSnapshot(r io.Reader) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, errors.Wrap(err, "json.Decoder.Decode()")
+	}
+
+	slices.SortFunc(snapshot.Resources, func(a, b ResourceEntry) int {
+		return cmp.Compare(a.Resource, b.Resource)
+	})
+	for i := range snapshot.Resources {
+		slices.Sort(snapshot.Resources[i].Permissions)
+	}
+
+	return snapshot, nil
+}
+
+// LoadPolicy decodes a JSON encoded accesstypes.Policy from r.
+func LoadPolicy(r io.Reader) (accesstypes.Policy, error) {
+	policy := make(accesstypes.Policy)
+	if err := json.NewDecoder(r).Decode(&policy); err != nil {
+		return nil, errors.Wrap(err, "json.Decoder.Decode()")
+	}
+
+	return policy, nil
+}
+
+// Row is a single entry in the access matrix.
+type Row struct {
+	Domain      accesstypes.Domain       `json:"domain"`
+	Role        accesstypes.Role         `json:"role"`
+	Resource    accesstypes.Resource     `json:"resource"`
+	Permissions []accesstypes.Permission `json:"permissions"`
+	// Denied are the permissions the role explicitly denies on the resource, along with the permissions it
+	// grants that a deny from another role in the domain or in GlobalDomain overrides
+	Denied []accesstypes.Permission `json:"denied,omitempty"`
+}
+
+// Grant is a single permission granted to a role on a resource.
+type Grant struct {
+	Domain     accesstypes.Domain     `json:"domain"`
+	Role       accesstypes.Role       `json:"role"`
+	Permission accesstypes.Permission `json:"permission"`
+	Resource   accesstypes.Resource   `json:"resource"`
+}
+
+// ResourcePermission is a permission declared on a resource.
+type ResourcePermission struct {
+	Resource   accesstypes.Resource   `json:"resource"`
+	Permission accesstypes.Permission `json:"permission"`
+}
+
+// Report is the result of reviewing a Policy against a Snapshot.
+type Report struct {
+	// Permissions is the sorted set of permissions that appear in the Matrix
+	Permissions []accesstypes.Permission `json:"permissions"`
+	// Matrix contains one row for every role, in every domain, that was granted or denied at least one
+	// permission on a resource declared in the Snapshot
+	Matrix []Row `json:"matrix"`
+	// DeniedGrants are grants overridden by a deny entry from any role in the domain or in GlobalDomain
+	DeniedGrants []Grant `json:"deniedGrants"`
+	// OrphanedGrants are grants on a permission that the resource does not declare,
+	// or on a resource that does not exist in the Snapshot
+	OrphanedGrants []Grant `json:"orphanedGrants"`
+	// UnreachableResources are declared resource permissions that no role is granted in any domain
+	UnreachableResources []ResourcePermission `json:"unreachableResources"`
+	// ImmutableUpdateGrants are Update grants on immutable fields
+	ImmutableUpdateGrants []Grant `json:"immutableUpdateGrants"`
+}
+
+// NewReport reviews policy against snapshot.
+//
+// Every grant is checked with accesstypes.Policy.Explain against all of the roles in its domain, along with
+// the roles in GlobalDomain, so deny-overrides semantics match Policy.Resolve: a grant that any of those roles
+// denies is reported in DeniedGrants and does not make the resource reachable.
+func NewReport(snapshot *Snapshot, policy accesstypes.Policy) *Report {
+	declared := make(map[accesstypes.Resource]ResourceEntry, len(snapshot.Resources))
+	for _, entry := range snapshot.Resources {
+		declared[entry.Resource] = entry
+	}
+
+	report := &Report{
+		Permissions:           []accesstypes.Permission{},
+		Matrix:                []Row{},
+		DeniedGrants:          []Grant{},
+		OrphanedGrants:        []Grant{},
+		UnreachableResources:  []ResourcePermission{},
+		ImmutableUpdateGrants: []Grant{},
+	}
+
+	reached := make(map[ResourcePermission]struct{})
+	permissions := make(map[accesstypes.Permission]struct{})
+
+	for _, domain := range slices.Sorted(maps.Keys(policy)) {
+		roles := policy[domain]
+		domainRoles := accesstypes.RoleCollection{domain: slices.Sorted(maps.Keys(roles))}
+		if domain != accesstypes.GlobalDomain {
+			domainRoles[accesstypes.GlobalDomain] = slices.Sorted(maps.Keys(policy[accesstypes.GlobalDomain]))
+		}
+
+		for _, role := range slices.Sorted(maps.Keys(roles)) {
+			rolePolicy := roles[role]
+			granted := make(map[accesstypes.Resource][]accesstypes.Permission)
+			denied := make(map[accesstypes.Resource][]accesstypes.Permission)
+			for _, permission := range slices.Sorted(maps.Keys(rolePolicy.Allow)) {
+				for _, res := range rolePolicy.Allow[permission] {
+					grant := Grant{Domain: domain, Role: role, Permission: permission, Resource: res}
+
+					entry, ok := declared[res]
+					if !ok || !slices.Contains(entry.Permissions, permission) {
+						report.OrphanedGrants = append(report.OrphanedGrants, grant)
+
+						continue
+					}
+
+					if !policy.Explain(domainRoles, domain, permission, res).Allowed {
+						report.DeniedGrants = append(report.DeniedGrants, grant)
+						permissions[permission] = struct{}{}
+						if !slices.Contains(denied[res], permission) {
+							denied[res] = append(denied[res], permission)
+						}
+
+						continue
+					}
+
+					if entry.Immutable && permission == accesstypes.Update {
+						report.ImmutableUpdateGrants = append(report.ImmutableUpdateGrants, grant)
+					}
+
+					reached[ResourcePermission{Resource: res, Permission: permission}] = struct{}{}
+					permissions[permission] = struct{}{}
+					if !slices.Contains(granted[res], permission) {
+						granted[res] = append(granted[res], permission)
+					}
+				}
+			}
+
+			for _, entry := range snapshot.Resources {
+				for _, permission := range entry.Permissions {
+					if rolePolicy.Denies(permission, entry.Resource) && !slices.Contains(denied[entry.Resource], permission) {
+						permissions[permission] = struct{}{}
+						denied[entry.Resource] = append(denied[entry.Resource], permission)
+					}
+				}
+			}
+
+			rowResources := slices.Collect(maps.Keys(granted))
+			for res := range denied {
+				if !slices.Contains(rowResources, res) {
+					rowResources = append(rowResources, res)
+				}
+			}
+			slices.Sort(rowResources)
+
+			for _, res := range rowResources {
+				perms := granted[res]
+				slices.Sort(perms)
+				deniedPerms := denied[res]
+				slices.Sort(deniedPerms)
+				report.Matrix = append(report.Matrix, Row{Domain: domain, Role: role, Resource: res, Permissions: perms, Denied: deniedPerms})
+			}
+		}
+	}
+
+	for _, entry := range snapshot.Resources {
+		for _, permission := range entry.Permissions {
+			rp := ResourcePermission{Resource: entry.Resource, Permission: permission}
+			if _, ok := reached[rp]; !ok {
+				report.UnreachableResources = append(report.UnreachableResources, rp)
+			}
+		}
+	}
+	slices.SortFunc(report.UnreachableResources, func(a, b ResourcePermission) int {
+		if a.Resource != b.Resource {
+			return cmp.Compare(a.Resource, b.Resource)
+		}
+
+		return cmp.Compare(a.Permission, b.Permission)
+	})
+
+	report.Permissions = slices.Sorted(maps.Keys(permissions))
+
+	return report
+}
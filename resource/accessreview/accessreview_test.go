@@ -0,0 +1,267 @@
+package accessreview
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Resources: []ResourceEntry{
+			{Resource: "Users", Scope: accesstypes.GlobalPermissionScope, Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update}},
+			{Resource: "Users.email", Scope: accesstypes.GlobalPermissionScope, Permissions: []accesstypes.Permission{accesstypes.Update}, Immutable: true},
+			{Resource: "Users.ssn", Scope: accesstypes.GlobalPermissionScope, Permissions: []accesstypes.Permission{accesstypes.Read}},
+		},
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy accesstypes.Policy
+		want   *Report
+	}{
+		{
+			name: "findings",
+			policy: accesstypes.Policy{
+				accesstypes.GlobalDomain: {
+					"Admin": {
+						Allow: accesstypes.RolePermissionCollection{
+							accesstypes.Read:   {"Users", "Users.ssn"},
+							accesstypes.Update: {"Users", "Users.email"},
+							accesstypes.Delete: {"Users"},
+						},
+					},
+					"Support": {
+						Allow: accesstypes.RolePermissionCollection{
+							accesstypes.Read: {"Users", "Orders"},
+						},
+					},
+				},
+			},
+			want: &Report{
+				Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update},
+				Matrix: []Row{
+					{Domain: accesstypes.GlobalDomain, Role: "Admin", Resource: "Users", Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update}},
+					{Domain: accesstypes.GlobalDomain, Role: "Admin", Resource: "Users.email", Permissions: []accesstypes.Permission{accesstypes.Update}},
+					{Domain: accesstypes.GlobalDomain, Role: "Admin", Resource: "Users.ssn", Permissions: []accesstypes.Permission{accesstypes.Read}},
+					{Domain: accesstypes.GlobalDomain, Role: "Support", Resource: "Users", Permissions: []accesstypes.Permission{accesstypes.Read}},
+				},
+				DeniedGrants: []Grant{},
+				OrphanedGrants: []Grant{
+					{Domain: accesstypes.GlobalDomain, Role: "Admin", Permission: accesstypes.Delete, Resource: "Users"},
+					{Domain: accesstypes.GlobalDomain, Role: "Support", Permission: accesstypes.Read, Resource: "Orders"},
+				},
+				UnreachableResources: []ResourcePermission{},
+				ImmutableUpdateGrants: []Grant{
+					{Domain: accesstypes.GlobalDomain, Role: "Admin", Permission: accesstypes.Update, Resource: "Users.email"},
+				},
+			},
+		},
+		{
+			name: "unreachable",
+			policy: accesstypes.Policy{
+				"tenant": {
+					"Viewer": {
+						Allow: accesstypes.RolePermissionCollection{
+							accesstypes.Read: {"Users"},
+						},
+					},
+				},
+			},
+			want: &Report{
+				Permissions: []accesstypes.Permission{accesstypes.Read},
+				Matrix: []Row{
+					{Domain: "tenant", Role: "Viewer", Resource: "Users", Permissions: []accesstypes.Permission{accesstypes.Read}},
+				},
+				DeniedGrants:   []Grant{},
+				OrphanedGrants: []Grant{},
+				UnreachableResources: []ResourcePermission{
+					{Resource: "Users", Permission: accesstypes.Update},
+					{Resource: "Users.email", Permission: accesstypes.Update},
+					{Resource: "Users.ssn", Permission: accesstypes.Read},
+				},
+				ImmutableUpdateGrants: []Grant{},
+			},
+		},
+		{
+			name: "deny overrides grant",
+			policy: accesstypes.Policy{
+				accesstypes.GlobalDomain: {
+					"Support": {
+						Allow: accesstypes.RolePermissionCollection{
+							accesstypes.Read:   {"Users", "Users.ssn"},
+							accesstypes.Update: {"Users"},
+						},
+						Deny: accesstypes.RolePermissionCollection{
+							accesstypes.Read: {"Users.ssn"},
+						},
+					},
+				},
+			},
+			want: &Report{
+				Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update},
+				Matrix: []Row{
+					{Domain: accesstypes.GlobalDomain, Role: "Support", Resource: "Users", Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update}},
+					{Domain: accesstypes.GlobalDomain, Role: "Support", Resource: "Users.ssn", Denied: []accesstypes.Permission{accesstypes.Read}},
+				},
+				DeniedGrants: []Grant{
+					{Domain: accesstypes.GlobalDomain, Role: "Support", Permission: accesstypes.Read, Resource: "Users.ssn"},
+				},
+				OrphanedGrants: []Grant{},
+				UnreachableResources: []ResourcePermission{
+					{Resource: "Users.email", Permission: accesstypes.Update},
+					{Resource: "Users.ssn", Permission: accesstypes.Read},
+				},
+				ImmutableUpdateGrants: []Grant{},
+			},
+		},
+		{
+			name: "deny from another role in the domain overrides grant",
+			policy: accesstypes.Policy{
+				accesstypes.GlobalDomain: {
+					"Auditor": {
+						Deny: accesstypes.RolePermissionCollection{
+							accesstypes.Read: {"Users"},
+						},
+					},
+				},
+				"tenant": {
+					"Support": {
+						Allow: accesstypes.RolePermissionCollection{
+							accesstypes.Read:   {"Users.ssn"},
+							accesstypes.Update: {"Users"},
+						},
+					},
+				},
+			},
+			want: &Report{
+				Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update},
+				Matrix: []Row{
+					{Domain: accesstypes.GlobalDomain, Role: "Auditor", Resource: "Users", Denied: []accesstypes.Permission{accesstypes.Read}},
+					{Domain: accesstypes.GlobalDomain, Role: "Auditor", Resource: "Users.ssn", Denied: []accesstypes.Permission{accesstypes.Read}},
+					{Domain: "tenant", Role: "Support", Resource: "Users", Permissions: []accesstypes.Permission{accesstypes.Update}},
+					{Domain: "tenant", Role: "Support", Resource: "Users.ssn", Denied: []accesstypes.Permission{accesstypes.Read}},
+				},
+				DeniedGrants: []Grant{
+					{Domain: "tenant", Role: "Support", Permission: accesstypes.Read, Resource: "Users.ssn"},
+				},
+				OrphanedGrants: []Grant{},
+				UnreachableResources: []ResourcePermission{
+					{Resource: "Users", Permission: accesstypes.Read},
+					{Resource: "Users.email", Permission: accesstypes.Update},
+					{Resource: "Users.ssn", Permission: accesstypes.Read},
+				},
+				ImmutableUpdateGrants: []Grant{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NewReport(testSnapshot(), tt.policy)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NewReport() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	t.Parallel()
+
+	report := NewReport(testSnapshot(), accesstypes.Policy{
+		accesstypes.GlobalDomain: {
+			"Support": {
+				Allow: accesstypes.RolePermissionCollection{
+					accesstypes.Read: {"Users", "Users.ssn"},
+				},
+				Deny: accesstypes.RolePermissionCollection{
+					accesstypes.Update: {"Users.email"},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		format  Format
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "csv",
+			format: CSVFormat,
+			want: "Domain,Role,Resource,Read,Update\n" +
+				"global,Support,Users,X,\n" +
+				"global,Support,Users.email,,DENY\n" +
+				"global,Support,Users.ssn,X,\n",
+		},
+		{
+			name:   "markdown",
+			format: MarkdownFormat,
+			want: "# Access Review\n\n## Access Matrix\n\n" +
+				"| Domain | Role | Resource | Read | Update |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| global | Support | Users | X |  |\n" +
+				"| global | Support | Users.email |  | DENY |\n" +
+				"| global | Support | Users.ssn | X |  |\n" +
+				"\n## Denied Grants\n\nNone\n" +
+				"\n## Orphaned Grants\n\nNone\n" +
+				"\n## Unreachable Resources\n\n" +
+				"| Resource | Permission |\n" +
+				"| --- | --- |\n" +
+				"| Users | Update |\n" +
+				"| Users.email | Update |\n" +
+				"\n## Immutable Fields Granted Update\n\nNone\n",
+		},
+		{
+			name:    "unsupported",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := report.Write(&buf, tt.format); (err != nil) != tt.wantErr {
+				t.Fatalf("Report.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Report.Write() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	t.Parallel()
+
+	got, err := LoadSnapshot(strings.NewReader(`{"resources":[
+		{"resource":"Users.ssn","scope":"global","permissions":["Read"]},
+		{"resource":"Users","scope":"global","permissions":["Update","Read"]}
+	]}`))
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+
+	want := &Snapshot{
+		Resources: []ResourceEntry{
+			{Resource: "Users", Scope: accesstypes.GlobalPermissionScope, Permissions: []accesstypes.Permission{accesstypes.Read, accesstypes.Update}},
+			{Resource: "Users.ssn", Scope: accesstypes.GlobalPermissionScope, Permissions: []accesstypes.Permission{accesstypes.Read}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadSnapshot() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,153 @@
+package accessreview
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+type Format string
+
+const (
+	CSVFormat      Format = "csv"
+	MarkdownFormat Format = "markdown"
+	JSONFormat     Format = "json"
+)
+
+// Write renders the report to w in the requested format.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case CSVFormat:
+		return r.WriteCSV(w)
+	case MarkdownFormat:
+		return r.WriteMarkdown(w)
+	case JSONFormat:
+		return r.WriteJSON(w)
+	default:
+		return errors.Newf("unsupported format %q", format)
+	}
+}
+
+// WriteCSV renders the access matrix as CSV with one column per permission.
+// Granted cells are marked X and explicitly denied cells are marked DENY.
+//
+// Findings are not included in the CSV output since they do not fit the matrix shape.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"Domain", "Role", "Resource"}
+	for _, perm := range r.Permissions {
+		header = append(header, string(perm))
+	}
+	if err := cw.Write(header); err != nil {
+		return errors.Wrap(err, "csv.Writer.Write()")
+	}
+
+	for _, row := range r.Matrix {
+		if err := cw.Write(append([]string{string(row.Domain), string(row.Role), string(row.Resource)}, r.cells(row)...)); err != nil {
+			return errors.Wrap(err, "csv.Writer.Write()")
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errors.Wrap(err, "csv.Writer.Flush()")
+	}
+
+	return nil
+}
+
+// WriteMarkdown renders the access matrix and findings as Markdown tables.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Access Review\n\n## Access Matrix\n\n")
+	header := []string{"Domain", "Role", "Resource"}
+	for _, perm := range r.Permissions {
+		header = append(header, string(perm))
+	}
+	writeMarkdownRow(&b, header)
+	writeMarkdownRow(&b, slices.Repeat([]string{"---"}, len(header)))
+	for _, row := range r.Matrix {
+		writeMarkdownRow(&b, append([]string{string(row.Domain), string(row.Role), string(row.Resource)}, r.cells(row)...))
+	}
+
+	b.WriteString("\n## Denied Grants\n\n")
+	writeMarkdownGrants(&b, r.DeniedGrants)
+
+	b.WriteString("\n## Orphaned Grants\n\n")
+	writeMarkdownGrants(&b, r.OrphanedGrants)
+
+	b.WriteString("\n## Unreachable Resources\n\n")
+	if len(r.UnreachableResources) == 0 {
+		b.WriteString("None\n")
+	} else {
+		writeMarkdownRow(&b, []string{"Resource", "Permission"})
+		writeMarkdownRow(&b, []string{"---", "---"})
+		for _, rp := range r.UnreachableResources {
+			writeMarkdownRow(&b, []string{string(rp.Resource), string(rp.Permission)})
+		}
+	}
+
+	b.WriteString("\n## Immutable Fields Granted Update\n\n")
+	writeMarkdownGrants(&b, r.ImmutableUpdateGrants)
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return errors.Wrap(err, "io.WriteString()")
+	}
+
+	return nil
+}
+
+// WriteJSON renders the full report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return errors.Wrap(err, "json.Encoder.Encode()")
+	}
+
+	return nil
+}
+
+func (r *Report) cells(row Row) []string {
+	cells := make([]string, 0, len(r.Permissions))
+	for _, perm := range r.Permissions {
+		switch {
+		case slices.Contains(row.Denied, perm):
+			cells = append(cells, "DENY")
+		case slices.Contains(row.Permissions, perm):
+			cells = append(cells, "X")
+		default:
+			cells = append(cells, "")
+		}
+	}
+
+	return cells
+}
+
+func writeMarkdownGrants(b *strings.Builder, grants []Grant) {
+	if len(grants) == 0 {
+		b.WriteString("None\n")
+
+		return
+	}
+
+	writeMarkdownRow(b, []string{"Domain", "Role", "Permission", "Resource"})
+	writeMarkdownRow(b, []string{"---", "---", "---", "---"})
+	for _, g := range grants {
+		writeMarkdownRow(b, []string{string(g.Domain), string(g.Role), string(g.Permission), string(g.Resource)})
+	}
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	for i := range cells {
+		cells[i] = strings.ReplaceAll(cells[i], "|", `\|`)
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+}
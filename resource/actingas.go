@@ -0,0 +1,123 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+type actingAsCtxKey struct{}
+
+// ActAs is the permission an impersonator must hold on ActingAsResource, in the domain of a request,
+// to act as another user.
+var ActAs = accesstypes.MustRegisterPermission("ActAs", accesstypes.PermissionDetail{
+	Description: "Act as another user",
+	Kind:        accesstypes.ActionPermissionKind,
+})
+
+// ActingAsResource is the resource the ActAs permission is granted on.
+const ActingAsResource accesstypes.Resource = "ActingAs"
+
+// ActingAs records that an authenticated user (the impersonator) is acting as another user.
+//
+// While acting as another user, permission checks in the decoders require the impersonator to be the
+// authenticated user and to hold ActAs on ActingAsResource, and evaluate the grants of User intersected
+// with the grants of Impersonator. UserEvent records both identities.
+type ActingAs struct {
+	// Impersonator is the real, authenticated user
+	Impersonator accesstypes.User
+	// ImpersonatorID is the ID of the impersonator recorded in change tracking
+	ImpersonatorID ccc.UUID
+	// User is the effective user whose grants are evaluated
+	User accesstypes.User
+	// UserID is the ID of the effective user recorded in change tracking
+	UserID ccc.UUID
+}
+
+func (a ActingAs) String() string {
+	return fmt.Sprintf("%s acting as %s", a.Impersonator, a.User)
+}
+
+// WithActingAs returns a copy of ctx carrying actingAs.
+func WithActingAs(ctx context.Context, actingAs ActingAs) context.Context {
+	return context.WithValue(ctx, actingAsCtxKey{}, actingAs)
+}
+
+// ActingAsFromCtx returns the ActingAs stored in ctx, if any.
+func ActingAsFromCtx(ctx context.Context) (ActingAs, bool) {
+	actingAs, ok := ctx.Value(actingAsCtxKey{}).(ActingAs)
+
+	return actingAs, ok
+}
+
+// requireResources checks that user holds perm on resources. When ctx carries a ServiceAccountInfo, the service
+// account's grants are evaluated instead of user's. When ctx carries an ActingAs, user must be the impersonator
+// and hold ActAs, and the effective user's grants are evaluated and intersected with the impersonator's grants.
+// subject describes who was checked, for use in error messages.
+func requireResources(
+	ctx context.Context, enforcer accesstypes.Enforcer, user accesstypes.User, domain accesstypes.Domain, perm accesstypes.Permission, resources ...accesstypes.Resource,
+) (ok bool, missing []accesstypes.Resource, subject string, err error) {
+	actingAs, found := ActingAsFromCtx(ctx)
+	if info, ok := ServiceAccountFromCtx(ctx); ok {
+		if found {
+			return false, nil, "", errors.Newf("service account %s can not act as user %s", info.ServiceAccount, actingAs.User)
+		}
+
+		saEnforcer, ok := enforcer.(accesstypes.ServiceAccountEnforcer)
+		if !ok {
+			return false, nil, "", errors.Newf("%T does not implement accesstypes.ServiceAccountEnforcer", enforcer)
+		}
+
+		ok, missing, err := saEnforcer.RequireServiceAccountResources(ctx, info.ServiceAccount, domain, perm, resources...)
+		if err != nil {
+			return false, nil, "", errors.Wrap(err, "accesstypes.ServiceAccountEnforcer.RequireServiceAccountResources()")
+		}
+
+		return ok, missing, "service account " + string(info.ServiceAccount), nil
+	}
+
+	if !found {
+		ok, missing, err := enforcer.RequireResources(ctx, user, domain, perm, resources...)
+
+		return ok, missing, "user " + string(user), err
+	}
+
+	if actingAs.Impersonator != user {
+		return false, nil, "", httpio.NewForbiddenMessagef("user %s can not act as user %s on behalf of %s", user, actingAs.User, actingAs.Impersonator)
+	}
+
+	canActAs, _, err := enforcer.RequireResources(ctx, actingAs.Impersonator, domain, ActAs, ActingAsResource)
+	if err != nil {
+		return false, nil, "", errors.Wrap(err, "accesstypes.Enforcer.RequireResources()")
+	}
+	if !canActAs {
+		return false, nil, "", httpio.NewForbiddenMessagef("user %s does not have %s permission on %s", actingAs.Impersonator, ActAs, ActingAsResource)
+	}
+
+	userOK, userMissing, err := enforcer.RequireResources(ctx, actingAs.User, domain, perm, resources...)
+	if err != nil {
+		return false, nil, "", errors.Wrap(err, "accesstypes.Enforcer.RequireResources()")
+	}
+
+	impersonatorOK, impersonatorMissing, err := enforcer.RequireResources(ctx, actingAs.Impersonator, domain, perm, resources...)
+	if err != nil {
+		return false, nil, "", errors.Wrap(err, "accesstypes.Enforcer.RequireResources()")
+	}
+
+	if userOK && impersonatorOK {
+		return true, nil, "user " + actingAs.String(), nil
+	}
+
+	for _, res := range resources {
+		if slices.Contains(userMissing, res) || slices.Contains(impersonatorMissing, res) {
+			missing = append(missing, res)
+		}
+	}
+
+	return false, missing, "user " + actingAs.String(), nil
+}
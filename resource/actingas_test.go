@@ -0,0 +1,110 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/ccc/accesstypes/accesstypestest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_requireResources(t *testing.T) {
+	t.Parallel()
+
+	enforcer := accesstypestest.NewEnforcer().
+		Grant(accesstypes.User("support"), accesstypes.GlobalDomain, accesstypes.Update, "Users", "Users.email").
+		Grant(accesstypes.User("support"), accesstypes.GlobalDomain, ActAs, ActingAsResource).
+		Grant(accesstypes.User("helpdesk"), accesstypes.GlobalDomain, accesstypes.Update, "Users").
+		Grant(accesstypes.User("customer"), accesstypes.GlobalDomain, accesstypes.Update, "Users", "Users.name")
+
+	tests := []struct {
+		name        string
+		actingAs    *ActingAs
+		user        accesstypes.User
+		resources   []accesstypes.Resource
+		wantOK      bool
+		wantMissing []accesstypes.Resource
+		wantSubject string
+		wantErr     bool
+	}{
+		{
+			name:        "user",
+			user:        "support",
+			resources:   []accesstypes.Resource{"Users", "Users.email"},
+			wantOK:      true,
+			wantSubject: "user support",
+		},
+		{
+			name:      "impersonator is not the authenticated user",
+			actingAs:  &ActingAs{Impersonator: "support", User: "customer"},
+			user:      "helpdesk",
+			resources: []accesstypes.Resource{"Users"},
+			wantErr:   true,
+		},
+		{
+			name:      "impersonator without ActAs",
+			actingAs:  &ActingAs{Impersonator: "helpdesk", User: "customer"},
+			user:      "helpdesk",
+			resources: []accesstypes.Resource{"Users"},
+			wantErr:   true,
+		},
+		{
+			name:        "acting as intersects grants",
+			actingAs:    &ActingAs{Impersonator: "support", User: "customer"},
+			user:        "support",
+			resources:   []accesstypes.Resource{"Users.email", "Users", "Users.name"},
+			wantMissing: []accesstypes.Resource{"Users.email", "Users.name"},
+			wantSubject: "user support acting as customer",
+		},
+		{
+			name:        "acting as with common grants",
+			actingAs:    &ActingAs{Impersonator: "support", User: "customer"},
+			user:        "support",
+			resources:   []accesstypes.Resource{"Users"},
+			wantOK:      true,
+			wantSubject: "user support acting as customer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.actingAs != nil {
+				ctx = WithActingAs(ctx, *tt.actingAs)
+			}
+
+			ok, missing, subject, err := requireResources(ctx, enforcer, tt.user, "tenant", accesstypes.Update, tt.resources...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requireResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("requireResources() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.wantMissing, missing); diff != "" {
+				t.Errorf("requireResources() missing mismatch (-want +got):\n%s", diff)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("requireResources() subject = %v, want %v", subject, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestUserEvent_actingAs(t *testing.T) {
+	t.Parallel()
+
+	actingAs := ActingAs{
+		Impersonator:   "support",
+		ImpersonatorID: ccc.Must(ccc.UUIDFromString("a517b48d-63a9-4c1f-b45b-8474b164e423")),
+		User:           "customer",
+		UserID:         ccc.Must(ccc.UUIDFromString("b517b48d-63a9-4c1f-b45b-8474b164e423")),
+	}
+
+	want := "support (a517b48d-63a9-4c1f-b45b-8474b164e423) acting as customer (b517b48d-63a9-4c1f-b45b-8474b164e423)"
+	if got := UserEvent(WithActingAs(context.Background(), actingAs)); got != want {
+		t.Errorf("UserEvent() = %q, want %q", got, want)
+	}
+}
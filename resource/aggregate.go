@@ -0,0 +1,556 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/apiv1/spannerpb"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+	"google.golang.org/api/iterator"
+)
+
+// AggregateFunc is an aggregate function computed over the rows matching a QuerySet.
+type AggregateFunc string
+
+const (
+	CountAggregate         AggregateFunc = "count"
+	CountDistinctAggregate AggregateFunc = "countDistinct"
+	SumAggregate           AggregateFunc = "sum"
+	MinAggregate           AggregateFunc = "min"
+	MaxAggregate           AggregateFunc = "max"
+)
+
+// Aggregate is an aggregate function over a field. For CountAggregate the Field may be
+// empty to count rows, otherwise only the rows where the field is not null are counted.
+type Aggregate struct {
+	Func  AggregateFunc
+	Field accesstypes.Field
+}
+
+// AggregateResult is a row of aggregate results. The group by values are keyed by the json name of
+// their field and the aggregate values by QuerySet.AggregateName. Null values are nil.
+type AggregateResult map[string]any
+
+// SetFieldMapper sets the FieldMapper of the Request type, whose json names key the AggregateResult
+// values. QueryDecoder.Decode sets it. Without it the values are keyed by the field names.
+func (q *QuerySet[Resource]) SetFieldMapper(fieldMapper *FieldMapper) *QuerySet[Resource] {
+	q.fieldMapper = fieldMapper
+
+	return q
+}
+
+// AggregateName returns the key of the aggregate in an AggregateResult, the aggregate function followed by
+// the json name of its field, such as count, countDistinctStatus or sumAmount.
+func (q *QuerySet[Resource]) AggregateName(a Aggregate) string {
+	name := q.resultName(a.Field)
+	if name == "" {
+		return string(a.Func)
+	}
+
+	return string(a.Func) + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// resultName returns the json name of field, or the field name without a FieldMapper.
+func (q *QuerySet[Resource]) resultName(field accesstypes.Field) string {
+	if q.fieldMapper != nil {
+		if name, ok := q.fieldMapper.JSONTag(field); ok {
+			return name
+		}
+	}
+
+	return string(field)
+}
+
+// AddAggregate adds an aggregate computed by SpannerAggregate, PostgresAggregate and SQLiteAggregate.
+func (q *QuerySet[Resource]) AddAggregate(aggregate Aggregate) *QuerySet[Resource] {
+	if !slices.Contains(q.aggregates, aggregate) {
+		q.aggregates = append(q.aggregates, aggregate)
+	}
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Aggregates() []Aggregate {
+	return q.aggregates
+}
+
+// GroupBy computes the aggregates for each distinct combination of fields. Without aggregates
+// the query returns the distinct values of the fields, which is useful for filter options.
+func (q *QuerySet[Resource]) GroupBy(fields ...accesstypes.Field) *QuerySet[Resource] {
+	q.groupBy = fields
+
+	return q
+}
+
+func (q *QuerySet[Resource]) GroupByFields() []accesstypes.Field {
+	return q.groupBy
+}
+
+// SpannerAggregate computes the aggregates over the rows matching the keys, filters and searches.
+func (q *QuerySet[Resource]) SpannerAggregate(ctx context.Context, txn *spanner.ReadOnlyTransaction) ([]AggregateResult, error) {
+	if q.rMeta.dbType != SpannerDBType {
+		return nil, errors.Newf("can only use SpannerAggregate() with dbType %s, got %s", SpannerDBType, q.rMeta.dbType)
+	}
+
+	if err := q.checkTimestampBound(); err != nil {
+		return nil, err
+	}
+
+	s, err := q.aggregateStmt()
+	if err != nil {
+		return nil, err
+	}
+	stmt := spanner.NewStatement(s.Sql)
+	maps.Insert(stmt.Params, maps.All(s.Params))
+
+	iter := txn.Query(ctx, stmt)
+	defer iter.Stop()
+
+	names := q.aggregateNames()
+	var results []AggregateResult
+	for {
+		row, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return results, nil
+			}
+
+			return nil, errors.Wrap(err, "spanner.RowIterator.Next()")
+		}
+
+		result, err := spannerAggregateResult(row, names)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+}
+
+// PostgresAggregate computes the aggregates over the rows matching the keys, filters and searches.
+func (q *QuerySet[Resource]) PostgresAggregate(ctx context.Context, db PostgresQuerier) ([]AggregateResult, error) {
+	if q.rMeta.dbType != PostgresDBType {
+		return nil, errors.Newf("can only use PostgresAggregate() with dbType %s, got %s", PostgresDBType, q.rMeta.dbType)
+	}
+
+	stmt, err := q.aggregateStmt()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := postgresQuery(ctx, db, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAggregateResults(rows, q.aggregateNames())
+}
+
+// SQLiteAggregate computes the aggregates over the rows matching the keys, filters and searches.
+func (q *QuerySet[Resource]) SQLiteAggregate(ctx context.Context, db SQLiteQuerier) ([]AggregateResult, error) {
+	if q.rMeta.dbType != SQLiteDBType {
+		return nil, errors.Newf("can only use SQLiteAggregate() with dbType %s, got %s", SQLiteDBType, q.rMeta.dbType)
+	}
+
+	stmt, err := q.aggregateStmt()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sqliteQuery(ctx, db, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAggregateResults(rows, q.aggregateNames())
+}
+
+// aggregateStmt builds the statement computing the aggregates for each group, ordered by the group by fields.
+func (q *QuerySet[Resource]) aggregateStmt() (Statement, error) {
+	if len(q.aggregates) == 0 && len(q.groupBy) == 0 {
+		return Statement{}, errors.New("no aggregates or group by fields set")
+	}
+
+	if q.cursor != "" {
+		return Statement{}, httpio.NewBadRequestMessage("cursor pagination is not supported with aggregates, use offset instead")
+	}
+
+	groups := make([]string, 0, len(q.groupBy))
+	for _, field := range q.groupBy {
+		c, ok := q.rMeta.fieldMap[field]
+		if !ok {
+			return Statement{}, errors.Newf("field %s not found in struct", field)
+		}
+
+		column, err := quoteIdent(q.rMeta.dbType, c.tag)
+		if err != nil {
+			return Statement{}, err
+		}
+		groups = append(groups, column)
+	}
+
+	columns := slices.Clone(groups)
+	for _, a := range q.aggregates {
+		expr, err := q.aggregateExpr(a)
+		if err != nil {
+			return Statement{}, err
+		}
+
+		alias, err := quoteIdent(q.rMeta.dbType, q.AggregateName(a))
+		if err != nil {
+			return Statement{}, err
+		}
+		columns = append(columns, expr+" AS "+alias)
+	}
+
+	where, err := q.matchWhere()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	var groupBy, order string
+	if len(groups) > 0 {
+		groupBy = "GROUP BY " + strings.Join(groups, ", ")
+
+		sortFields, err := q.aggregateOrder()
+		if err != nil {
+			return Statement{}, err
+		}
+
+		terms, err := orderBy(q.rMeta.dbType, q.rMeta.fieldMap, sortFields)
+		if err != nil {
+			return Statement{}, errors.Wrap(err, "orderBy()")
+		}
+		order = "ORDER BY " + terms
+	}
+
+	limit, err := q.limitClause(q.limit)
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.limitClause()")
+	}
+
+	table, err := q.table()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	sql := fmt.Sprintf(`
+			SELECT
+				%s
+			FROM %s
+			%s
+			%s
+			%s
+			%s`, strings.Join(columns, ", "), table, where.Sql, groupBy, order, limit,
+	)
+
+	return Statement{
+		Sql:    sql,
+		Params: where.Params,
+	}, nil
+}
+
+// aggregateOrder returns the sort fields followed by the remaining group by fields, so that groups
+// are returned in a deterministic order. Only group by fields can be sorted.
+func (q *QuerySet[Resource]) aggregateOrder() ([]SortField, error) {
+	order := make([]SortField, 0, len(q.groupBy))
+	for _, s := range q.sort {
+		if !slices.Contains(q.groupBy, s.Field) {
+			return nil, httpio.NewBadRequestMessagef("sort field %s must be grouped by to sort aggregates", s.Field)
+		}
+		order = append(order, s)
+	}
+
+	for _, field := range q.groupBy {
+		if !slices.ContainsFunc(order, func(s SortField) bool { return s.Field == field }) {
+			order = append(order, SortField{Field: field, Direction: Ascending})
+		}
+	}
+
+	return order, nil
+}
+
+func (q *QuerySet[Resource]) aggregateExpr(a Aggregate) (string, error) {
+	if a.Field == "" {
+		if a.Func == CountAggregate {
+			return "COUNT(*)", nil
+		}
+
+		return "", errors.Newf("aggregate %s requires a field", a.Func)
+	}
+
+	c, ok := q.rMeta.fieldMap[a.Field]
+	if !ok {
+		return "", errors.Newf("field %s not found in struct", a.Field)
+	}
+
+	column, err := quoteIdent(q.rMeta.dbType, c.tag)
+	if err != nil {
+		return "", err
+	}
+
+	switch a.Func {
+	case CountAggregate:
+		return fmt.Sprintf("COUNT(%s)", column), nil
+	case CountDistinctAggregate:
+		return fmt.Sprintf("COUNT(DISTINCT %s)", column), nil
+	case SumAggregate:
+		return fmt.Sprintf("SUM(%s)", column), nil
+	case MinAggregate:
+		return fmt.Sprintf("MIN(%s)", column), nil
+	case MaxAggregate:
+		return fmt.Sprintf("MAX(%s)", column), nil
+	default:
+		return "", errors.Newf("unsupported aggregate: %s", a.Func)
+	}
+}
+
+// aggregateNames returns the AggregateResult keys in the order of the selected columns.
+func (q *QuerySet[Resource]) aggregateNames() []string {
+	names := make([]string, 0, len(q.groupBy)+len(q.aggregates))
+	for _, field := range q.groupBy {
+		names = append(names, q.resultName(field))
+	}
+	for _, a := range q.aggregates {
+		names = append(names, q.AggregateName(a))
+	}
+
+	return names
+}
+
+// scanAggregateResults scans each row into an AggregateResult keyed by names.
+func scanAggregateResults(rows rowScanner, names []string) ([]AggregateResult, error) {
+	var results []AggregateResult
+	for rows.Next() {
+		values := make([]any, len(names))
+		dests := make([]any, len(names))
+		for i := range values {
+			dests[i] = &values[i]
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return nil, errors.Wrap(err, "rows.Scan()")
+		}
+
+		result := make(AggregateResult, len(names))
+		for i, name := range names {
+			result[name] = values[i]
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows.Err()")
+	}
+
+	return results, nil
+}
+
+func spannerAggregateResult(row *spanner.Row, names []string) (AggregateResult, error) {
+	if row.Size() != len(names) {
+		return nil, errors.Newf("expected %d columns, got %d", len(names), row.Size())
+	}
+
+	result := make(AggregateResult, len(names))
+	for i, name := range names {
+		var col spanner.GenericColumnValue
+		if err := row.Column(i, &col); err != nil {
+			return nil, errors.Wrap(err, "spanner.Row.Column()")
+		}
+
+		value, err := spannerValue(col)
+		if err != nil {
+			return nil, errors.Wrapf(err, "column %s", name)
+		}
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+// spannerValue decodes a scalar column value, returning nil for null.
+func spannerValue(col spanner.GenericColumnValue) (any, error) {
+	switch col.Type.GetCode() {
+	case spannerpb.TypeCode_INT64:
+		var v spanner.NullInt64
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return v.Int64, nil
+	case spannerpb.TypeCode_FLOAT64:
+		var v spanner.NullFloat64
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return v.Float64, nil
+	case spannerpb.TypeCode_NUMERIC:
+		var v spanner.NullNumeric
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return &v.Numeric, nil
+	case spannerpb.TypeCode_STRING:
+		var v spanner.NullString
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return v.StringVal, nil
+	case spannerpb.TypeCode_BOOL:
+		var v spanner.NullBool
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return v.Bool, nil
+	case spannerpb.TypeCode_TIMESTAMP:
+		var v spanner.NullTime
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return v.Time, nil
+	case spannerpb.TypeCode_DATE:
+		var v spanner.NullDate
+		if err := col.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "spanner.GenericColumnValue.Decode()")
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+
+		return v.Date, nil
+	default:
+		return nil, errors.Newf("unsupported column type %s", col.Type.GetCode())
+	}
+}
+
+// parseAggregateParams parses the aggregate and groupBy query parameters.
+//
+// The aggregate parameter is a comma separated list of func or func:field, where field is the
+// json name of a request field, and groupBy is a comma separated list of fields. For example:
+//
+//	?aggregate=count,countDistinct:status,sum:count&groupBy=status
+func parseAggregateParams(fieldMapper *FieldMapper, resourceType reflect.Type, fieldMap map[accesstypes.Field]cacheEntry, queryParams url.Values) ([]Aggregate, []accesstypes.Field, error) {
+	field := func(name string) (accesstypes.Field, reflect.Type, error) {
+		field, found := fieldMapper.StructFieldName(name)
+		if !found {
+			return "", nil, httpio.NewBadRequestMessagef("unknown aggregate field: %s", name)
+		}
+
+		c, ok := fieldMap[field]
+		if !ok {
+			return "", nil, httpio.NewBadRequestMessagef("field %s can not be aggregated", name)
+		}
+
+		return field, resourceType.Field(c.index).Type, nil
+	}
+
+	var aggregates []Aggregate
+	if param := queryParams.Get("aggregate"); param != "" {
+		for _, item := range strings.Split(param, ",") {
+			fn, name, _ := strings.Cut(item, ":")
+
+			a := Aggregate{Func: AggregateFunc(fn)}
+			switch a.Func {
+			case CountAggregate, CountDistinctAggregate, SumAggregate, MinAggregate, MaxAggregate:
+			default:
+				return nil, nil, httpio.NewBadRequestMessagef("unknown aggregate: %s", fn)
+			}
+
+			if name == "" && a.Func != CountAggregate {
+				return nil, nil, httpio.NewBadRequestMessagef("aggregate %s requires a field", fn)
+			}
+
+			if name != "" {
+				f, typ, err := field(name)
+				if err != nil {
+					return nil, nil, err
+				}
+				if a.Func == SumAggregate && !isNumeric(typ) {
+					return nil, nil, httpio.NewBadRequestMessagef("aggregate %s requires a numeric field, got %s", fn, name)
+				}
+				a.Field = f
+			}
+
+			if slices.Contains(aggregates, a) {
+				return nil, nil, httpio.NewBadRequestMessagef("aggregate %s is repeated", item)
+			}
+			aggregates = append(aggregates, a)
+		}
+	}
+
+	var groupBy []accesstypes.Field
+	if param := queryParams.Get("groupBy"); param != "" {
+		for _, name := range strings.Split(param, ",") {
+			f, _, err := field(name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if slices.Contains(groupBy, f) {
+				return nil, nil, httpio.NewBadRequestMessagef("groupBy field %s is repeated", name)
+			}
+			groupBy = append(groupBy, f)
+		}
+	}
+
+	return aggregates, groupBy, nil
+}
+
+var (
+	bigRatType         = reflect.TypeFor[big.Rat]()
+	spannerNumericType = reflect.TypeFor[spanner.NullNumeric]()
+	spannerInt64Type   = reflect.TypeFor[spanner.NullInt64]()
+	spannerFloat64Type = reflect.TypeFor[spanner.NullFloat64]()
+)
+
+// isNumeric reports whether values of typ can be summed.
+func isNumeric(typ reflect.Type) bool {
+	typ = baseType(typ)
+
+	switch typ {
+	case bigRatType, spannerNumericType, spannerInt64Type, spannerFloat64Type:
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,274 @@
+package resource
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseAggregateParams(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := NewFieldMapper(filterRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+	fieldMap := structTags(reflect.TypeFor[filterResource](), string(SpannerDBType))
+
+	tests := []struct {
+		name           string
+		query          url.Values
+		wantAggregates []Aggregate
+		wantGroupBy    []accesstypes.Field
+		wantErr        bool
+	}{
+		{
+			name:  "no aggregates",
+			query: url.Values{},
+		},
+		{
+			name:  "aggregates and group by",
+			query: url.Values{"aggregate": {"count,countDistinct:id,sum:count,min:createdAt,max:createdAt"}, "groupBy": {"status"}},
+			wantAggregates: []Aggregate{
+				{Func: CountAggregate},
+				{Func: CountDistinctAggregate, Field: "ID"},
+				{Func: SumAggregate, Field: "Count"},
+				{Func: MinAggregate, Field: "CreatedAt"},
+				{Func: MaxAggregate, Field: "CreatedAt"},
+			},
+			wantGroupBy: []accesstypes.Field{"Status"},
+		},
+		{
+			name:        "distinct values",
+			query:       url.Values{"groupBy": {"status,deletedAt"}},
+			wantGroupBy: []accesstypes.Field{"Status", "DeletedAt"},
+		},
+		{name: "unknown aggregate", query: url.Values{"aggregate": {"avg:count"}}, wantErr: true},
+		{name: "missing field", query: url.Values{"aggregate": {"sum"}}, wantErr: true},
+		{name: "unknown field", query: url.Values{"aggregate": {"max:nope"}}, wantErr: true},
+		{name: "field without column", query: url.Values{"groupBy": {"hidden"}}, wantErr: true},
+		{name: "sum of string", query: url.Values{"aggregate": {"sum:status"}}, wantErr: true},
+		{name: "repeated aggregate", query: url.Values{"aggregate": {"count,count"}}, wantErr: true},
+		{name: "repeated group by", query: url.Values{"groupBy": {"status,status"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			aggregates, groupBy, err := parseAggregateParams(mapper, reflect.TypeFor[filterResource](), fieldMap, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAggregateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantAggregates, aggregates); diff != "" {
+				t.Errorf("parseAggregateParams() aggregates mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantGroupBy, groupBy); diff != "" {
+				t.Errorf("parseAggregateParams() groupBy mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_aggregateStmt(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := NewFieldMapper(filterRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		dbType     DBType
+		prepare    func(q *QuerySet[filterResource])
+		wantSql    string
+		wantParams map[string]any
+		wantErr    bool
+	}{
+		{
+			name:   "spanner count",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: CountAggregate})
+			},
+			wantSql: `SELECT COUNT(*) AS count FROM FilterResources`,
+		},
+		{
+			name:   "spanner grouped with filter",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: CountAggregate}).
+					AddAggregate(Aggregate{Func: SumAggregate, Field: "Count"}).
+					AddAggregate(Aggregate{Func: CountDistinctAggregate, Field: "ID"}).
+					AddFilter(Filter{Field: "Count", Operator: GreaterThanOperator, Value: int64(1)}).
+					GroupBy("Status").
+					SetLimit(5)
+			},
+			wantSql: `SELECT Status, COUNT(*) AS count, SUM(Count) AS sumCount, COUNT(DISTINCT Id) AS countDistinctID FROM FilterResources ` +
+				`WHERE Count > @filter0 GROUP BY Status ORDER BY Status ASC LIMIT 5`,
+			wantParams: map[string]any{"filter0": int64(1)},
+		},
+		{
+			name:   "spanner json names",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: CountDistinctAggregate, Field: "ID"}).
+					AddAggregate(Aggregate{Func: MaxAggregate, Field: "CreatedAt"}).
+					GroupBy("Status").
+					SetFieldMapper(mapper)
+			},
+			wantSql: `SELECT Status, COUNT(DISTINCT Id) AS countDistinctId, MAX(CreatedAt) AS maxCreatedAt FROM FilterResources ` +
+				`GROUP BY Status ORDER BY Status ASC`,
+		},
+		{
+			name:   "spanner with search",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: MaxAggregate, Field: "CreatedAt"})
+				q.SetSearchParam(NewSearchSet(Ngram, "StatusNgrams", "act"))
+			},
+			wantSql:    `SELECT MAX(CreatedAt) AS maxCreatedAt FROM FilterResources WHERE (SEARCH_NGRAMS(StatusNgrams, @ngramsterm0))`,
+			wantParams: map[string]any{"ngramsterm0": "act"},
+		},
+		{
+			name:   "postgres distinct values sorted",
+			dbType: PostgresDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.GroupBy("Status", "Count").OrderBy(SortField{Field: "Count", Direction: Descending})
+				q.SetKey("ID", "abc")
+			},
+			wantSql:    `SELECT "status", "count" FROM "FilterResources" WHERE "id" = @id GROUP BY "status", "count" ORDER BY "count" DESC NULLS LAST, "status" ASC NULLS FIRST`,
+			wantParams: map[string]any{"id": "abc"},
+		},
+		{
+			name:   "postgres min",
+			dbType: PostgresDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: MinAggregate, Field: "CreatedAt"})
+			},
+			wantSql: `SELECT MIN("created_at") AS "minCreatedAt" FROM "FilterResources"`,
+		},
+		{
+			name:    "nothing to aggregate",
+			dbType:  SpannerDBType,
+			prepare: func(*QuerySet[filterResource]) {},
+			wantErr: true,
+		},
+		{
+			name:   "sort by field not grouped",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: CountAggregate}).GroupBy("Status").OrderBy(SortField{Field: "Count"})
+			},
+			wantErr: true,
+		},
+		{
+			name:   "aggregate without field",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: SumAggregate})
+			},
+			wantErr: true,
+		},
+		{
+			name:   "cursor",
+			dbType: SpannerDBType,
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddAggregate(Aggregate{Func: CountAggregate}).SetCursor("abc")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(tt.dbType)
+			tt.prepare(q)
+
+			got, err := q.aggregateStmt()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuerySet.aggregateStmt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantSql, strings.Join(strings.Fields(got.Sql), " ")); diff != "" {
+				t.Errorf("QuerySet.aggregateStmt() SQL mismatch (-want +got):\n%s", diff)
+			}
+			if len(tt.wantParams) == 0 && len(got.Params) == 0 {
+				return
+			}
+			if diff := cmp.Diff(tt.wantParams, got.Params); diff != "" {
+				t.Errorf("QuerySet.aggregateStmt() params mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_SQLiteAggregate(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLiteDB(t)
+	if _, err := db.Exec(`INSERT INTO FilterResources ("id", "status", "count") VALUES ('d', 'closed', 4), ('e', 'closed again', 5)`); err != nil {
+		t.Fatalf("sql.DB.Exec() error = %v", err)
+	}
+
+	mapper, err := NewFieldMapper(filterRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+
+	q := newFilterQuerySet(SQLiteDBType).
+		AddAggregate(Aggregate{Func: CountAggregate}).
+		AddAggregate(Aggregate{Func: SumAggregate, Field: "Count"}).
+		AddFilter(Filter{Field: "Count", Operator: GreaterThanOperator, Value: 1}).
+		GroupBy("Status").
+		OrderBy(SortField{Field: "Status", Direction: Descending}).
+		SetFieldMapper(mapper)
+	q.SetSearchParam(NewSearchSet(SubString, "status", "e"))
+
+	got, err := q.SQLiteAggregate(context.Background(), db)
+	if err != nil {
+		t.Fatalf("QuerySet.SQLiteAggregate() error = %v", err)
+	}
+
+	want := []AggregateResult{
+		{"status": "pending review", "count": int64(1), "sumCount": int64(2)},
+		{"status": "closed again", "count": int64(1), "sumCount": int64(5)},
+		{"status": "closed", "count": int64(1), "sumCount": int64(4)},
+		{"status": "50% done", "count": int64(1), "sumCount": int64(3)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("QuerySet.SQLiteAggregate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_spannerAggregateResult(t *testing.T) {
+	t.Parallel()
+
+	row, err := spanner.NewRow(
+		[]string{"Status", "count", "maxCount"},
+		[]any{"active", int64(3), spanner.NullInt64{}},
+	)
+	if err != nil {
+		t.Fatalf("spanner.NewRow() error = %v", err)
+	}
+
+	got, err := spannerAggregateResult(row, []string{"status", "count", "maxCount"})
+	if err != nil {
+		t.Fatalf("spannerAggregateResult() error = %v", err)
+	}
+
+	want := AggregateResult{"status": "active", "count": int64(3), "maxCount": nil}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("spannerAggregateResult() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,367 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/spxscan"
+	"github.com/go-playground/errors/v5"
+)
+
+// AddKeySet adds the primary key of a row to read with SpannerBatchRead, PostgresBatchRead or SQLiteBatchRead.
+func (q *QuerySet[Resource]) AddKeySet(keySet KeySet) *QuerySet[Resource] {
+	q.keySets = append(q.keySets, keySet)
+
+	return q
+}
+
+func (q *QuerySet[Resource]) KeySets() []KeySet {
+	return q.keySets
+}
+
+// SpannerBatchRead reads the rows for the key sets added with AddKeySet into dst, a pointer to a slice of
+// struct pointers. dst is aligned with KeySets: each element holds the row for the key set at the same index,
+// or nil when it was not found, and the key sets which were not found are returned.
+//
+// A single column primary key is read with one query, to which the filters and searches still apply.
+// Composite primary keys are read with the Read API, which does not support filters or searches.
+func (q *QuerySet[Resource]) SpannerBatchRead(ctx context.Context, txn *spanner.ReadOnlyTransaction, dst any) (notFound []KeySet, err error) {
+	if q.rMeta.dbType != SpannerDBType {
+		return nil, errors.Newf("can only use SpannerBatchRead() with dbType %s, got %s", SpannerDBType, q.rMeta.dbType)
+	}
+
+	if err := q.checkTimestampBound(); err != nil {
+		return nil, err
+	}
+
+	batch, err := q.batch()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := batchRows(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(q.rMeta.primaryKeys) == 1 {
+		s, err := batch.batchStmt()
+		if err != nil {
+			return nil, err
+		}
+		stmt := spanner.NewStatement(s.Sql)
+		maps.Insert(stmt.Params, maps.All(s.Params))
+
+		if err := spxscan.Select(ctx, txn, rows.Interface(), stmt); err != nil {
+			return nil, errors.Wrap(err, "spxscan.Select()")
+		}
+	} else {
+		if len(q.filters) > 0 || len(q.searches) > 0 || len(q.expansions) > 0 {
+			return nil, errors.New("filters, searches and expansions are not supported when batch reading composite primary keys")
+		}
+
+		columns, err := batch.columnTags()
+		if err != nil {
+			return nil, errors.Wrap(err, "QuerySet.columnTags()")
+		}
+
+		keys := make([]spanner.KeySet, 0, len(batch.keySets))
+		for _, keySet := range batch.keySets {
+			keys = append(keys, keySet.KeySet())
+		}
+
+		elemType := rows.Elem().Type().Elem().Elem()
+		iter := txn.Read(ctx, string(q.Resource()), spanner.KeySets(keys...), columns)
+		if err := iter.Do(func(row *spanner.Row) error {
+			elem := reflect.New(elemType)
+			if err := row.ToStruct(elem.Interface()); err != nil {
+				return errors.Wrap(err, "spanner.Row.ToStruct()")
+			}
+			rows.Elem().Set(reflect.Append(rows.Elem(), elem))
+
+			return nil
+		}); err != nil {
+			return nil, errors.Wrap(err, "spanner.RowIterator.Do()")
+		}
+	}
+
+	return batch.alignBatch(rows.Elem(), dst)
+}
+
+// PostgresBatchRead reads the rows for the key sets added with AddKeySet into dst, a pointer to a slice of
+// struct pointers with postgres tags. dst is aligned with KeySets: each element holds the row for the key set at
+// the same index, or nil when it was not found, and the key sets which were not found are returned.
+func (q *QuerySet[Resource]) PostgresBatchRead(ctx context.Context, db PostgresQuerier, dst any) (notFound []KeySet, err error) {
+	if q.rMeta.dbType != PostgresDBType {
+		return nil, errors.Newf("can only use PostgresBatchRead() with dbType %s, got %s", PostgresDBType, q.rMeta.dbType)
+	}
+
+	batch, err := q.batch()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := batchRows(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := batch.batchStmt()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := postgresQuery(ctx, db, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postgresSelect(result, rows.Interface()); err != nil {
+		return nil, err
+	}
+
+	return batch.alignBatch(rows.Elem(), dst)
+}
+
+// SQLiteBatchRead reads the rows for the key sets added with AddKeySet into dst, a pointer to a slice of
+// struct pointers with sqlite tags. dst is aligned with KeySets: each element holds the row for the key set at
+// the same index, or nil when it was not found, and the key sets which were not found are returned.
+func (q *QuerySet[Resource]) SQLiteBatchRead(ctx context.Context, db SQLiteQuerier, dst any) (notFound []KeySet, err error) {
+	if q.rMeta.dbType != SQLiteDBType {
+		return nil, errors.Newf("can only use SQLiteBatchRead() with dbType %s, got %s", SQLiteDBType, q.rMeta.dbType)
+	}
+
+	batch, err := q.batch()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := batchRows(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := batch.batchStmt()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := sqliteQuery(ctx, db, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqliteSelect(result, rows.Interface()); err != nil {
+		return nil, err
+	}
+
+	return batch.alignBatch(rows.Elem(), dst)
+}
+
+// batch returns a copy of the QuerySet which also selects the primary key, with each key set
+// ordered by the primary key fields.
+func (q *QuerySet[Resource]) batch() (*QuerySet[Resource], error) {
+	if len(q.keySets) == 0 {
+		return nil, errors.New("no key sets added for batch read")
+	}
+
+	batch := *q
+	batch.fields = slices.Clone(q.fields)
+	for _, key := range q.rMeta.primaryKeys {
+		batch.AddField(key)
+	}
+
+	batch.keySets = make([]KeySet, 0, len(q.keySets))
+	for _, keySet := range q.keySets {
+		if keySet.Len() != len(q.rMeta.primaryKeys) {
+			return nil, errors.Newf("key set has %d parts, %s has %d primary key fields", keySet.Len(), q.Resource(), len(q.rMeta.primaryKeys))
+		}
+
+		keyMap := keySet.KeyMap()
+		var ordered KeySet
+		for _, key := range q.rMeta.primaryKeys {
+			value, ok := keyMap[key]
+			if !ok {
+				return nil, errors.Newf("key set (%s) is missing primary key field %s", keySet.String(), key)
+			}
+			ordered = ordered.Add(key, value)
+		}
+		batch.keySets = append(batch.keySets, ordered)
+	}
+
+	return &batch, nil
+}
+
+// batchStmt builds the statement selecting the rows for all of the key sets.
+func (q *QuerySet[Resource]) batchStmt() (Statement, error) {
+	columns, err := q.Columns()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.Columns()")
+	}
+
+	expansions, err := q.expansionColumns()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.expansionColumns()")
+	}
+
+	keys, err := q.batchWhere()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	where, err := q.matchWhere()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	whereSql := "WHERE " + keys.Sql
+	if where.Sql != "" {
+		whereSql = "WHERE (" + keys.Sql + ") AND " + strings.TrimPrefix(where.Sql, "WHERE ")
+	}
+	maps.Insert(keys.Params, maps.All(where.Params))
+
+	table, err := q.table()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	sql := fmt.Sprintf(`
+			SELECT
+				%s%s
+			FROM %s
+			%s`, columns, expansions, table, whereSql,
+	)
+
+	return Statement{
+		Sql:    sql,
+		Params: keys.Params,
+	}, nil
+}
+
+// batchWhere returns the condition matching any of the key sets.
+func (q *QuerySet[Resource]) batchWhere() (Statement, error) {
+	return q.keySetsWhere(q.keySets)
+}
+
+// keySetsWhere returns the condition matching any of keySets, which must all have the same fields. Single
+// field key sets are matched against an array of the key values, and composite key sets are matched one at a time.
+func (q *QuerySet[Resource]) keySetsWhere(keySets []KeySet) (Statement, error) {
+	if keySets[0].Len() == 1 {
+		key := keySets[0].Parts()[0].Key
+		c, ok := q.rMeta.fieldMap[key]
+		if !ok {
+			return Statement{}, errors.Newf("field %s not found in struct", key)
+		}
+
+		values, err := batchKeyValues(keySets)
+		if err != nil {
+			return Statement{}, err
+		}
+
+		sql, value, err := filterCondition(q.rMeta.dbType, c.tag, Filter{Field: key, Operator: InOperator, Value: values}, "batchkeys")
+		if err != nil {
+			return Statement{}, errors.Wrap(err, "filterCondition()")
+		}
+
+		return Statement{
+			Sql:    sql,
+			Params: map[string]any{"batchkeys": value},
+		}, nil
+	}
+
+	conditions := make([]string, 0, len(keySets))
+	params := make(map[string]any)
+	for i, keySet := range keySets {
+		parts := make([]string, 0, keySet.Len())
+		for j, part := range keySet.Parts() {
+			c, ok := q.rMeta.fieldMap[part.Key]
+			if !ok {
+				return Statement{}, errors.Newf("field %s not found in struct", part.Key)
+			}
+
+			column, err := quoteIdent(q.rMeta.dbType, c.tag)
+			if err != nil {
+				return Statement{}, err
+			}
+
+			param := fmt.Sprintf("batch%dkey%d", i, j)
+			parts = append(parts, fmt.Sprintf("%s = @%s", column, param))
+			params[param] = part.Value
+		}
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return Statement{
+		Sql:    strings.Join(conditions, " OR "),
+		Params: params,
+	}, nil
+}
+
+// batchKeyValues returns the values of single part key sets as a slice of their type, as required by Spanner array parameters.
+func batchKeyValues(keySets []KeySet) (any, error) {
+	typ := reflect.TypeOf(keySets[0].Parts()[0].Value)
+	if typ == nil {
+		return nil, errors.New("key set values can not be nil")
+	}
+
+	values := reflect.MakeSlice(reflect.SliceOf(typ), 0, len(keySets))
+	for _, keySet := range keySets {
+		value := reflect.ValueOf(keySet.Parts()[0].Value)
+		if value.Type() != typ {
+			return nil, errors.Newf("key set values must all be of type %s, got %s", typ, value.Type())
+		}
+		values = reflect.Append(values, value)
+	}
+
+	return values.Interface(), nil
+}
+
+// batchRows returns a pointer to a new, empty slice of the type of dst for scanning the rows.
+func batchRows(dst any) (reflect.Value, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, errors.Newf("dst must be a pointer to a slice, got %T", dst)
+	}
+
+	elemType := v.Elem().Type().Elem()
+	if elemType.Kind() != reflect.Pointer || elemType.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.Newf("dst must be a pointer to a slice of struct pointers, got %T", dst)
+	}
+
+	return reflect.New(v.Elem().Type()), nil
+}
+
+// alignBatch sets dst to hold the row for each key set at its index, returning the key sets without a row.
+// Rows are matched to key sets by the primary key fields of the row struct.
+func (q *QuerySet[Resource]) alignBatch(rows reflect.Value, dst any) ([]KeySet, error) {
+	byRowID := make(map[string]reflect.Value, rows.Len())
+	for i := range rows.Len() {
+		row := rows.Index(i)
+
+		keySet, err := rowKeySet(row, q.rMeta.primaryKeys)
+		if err != nil {
+			return nil, err
+		}
+		byRowID[keySet.RowID()] = row
+	}
+
+	aligned := reflect.MakeSlice(rows.Type(), len(q.keySets), len(q.keySets))
+	var notFound []KeySet
+	for i, keySet := range q.keySets {
+		row, ok := byRowID[keySet.RowID()]
+		if !ok {
+			notFound = append(notFound, keySet)
+
+			continue
+		}
+		aligned.Index(i).Set(row)
+	}
+	reflect.ValueOf(dst).Elem().Set(aligned)
+
+	return notFound, nil
+}
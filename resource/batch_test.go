@@ -0,0 +1,193 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+type tenantResource struct {
+	TenantID string `spanner:"TenantId" postgres:"tenant_id" sqlite:"tenant_id" query:"primaryKey"`
+	ID       string `spanner:"Id"       postgres:"id"        sqlite:"id"        query:"primaryKey"`
+	Name     string `spanner:"Name"     postgres:"name"      sqlite:"name"`
+}
+
+func (tenantResource) Resource() accesstypes.Resource {
+	return "TenantResources"
+}
+
+func (tenantResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+func newTenantQuerySet(dbType DBType) *QuerySet[tenantResource] {
+	return NewQuerySet(&ResourceMetadata[tenantResource]{
+		fieldMap:    structTags(reflect.TypeFor[tenantResource](), string(dbType)),
+		primaryKeys: primaryKeyFields(reflect.TypeFor[tenantResource]()),
+		dbType:      dbType,
+	})
+}
+
+func TestQuerySet_batchStmt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("spanner single key", func(t *testing.T) {
+		t.Parallel()
+
+		q := newFilterQuerySet(SpannerDBType).AddField("Status").
+			AddKeySet(KeySet{}.Add("ID", "a")).
+			AddKeySet(KeySet{}.Add("ID", "b")).
+			AddFilter(Filter{Field: "Status", Operator: EqualOperator, Value: "active"})
+
+		batch, err := q.batch()
+		if err != nil {
+			t.Fatalf("QuerySet.batch() error = %v", err)
+		}
+		got, err := batch.batchStmt()
+		if err != nil {
+			t.Fatalf("QuerySet.batchStmt() error = %v", err)
+		}
+
+		if diff := cmp.Diff(`SELECT Id, Status FROM FilterResources WHERE (Id IN UNNEST(@batchkeys)) AND Status = @filter0`, strings.Join(strings.Fields(got.Sql), " ")); diff != "" {
+			t.Errorf("QuerySet.batchStmt() SQL mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(map[string]any{"batchkeys": []string{"a", "b"}, "filter0": "active"}, got.Params); diff != "" {
+			t.Errorf("QuerySet.batchStmt() params mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("postgres composite key", func(t *testing.T) {
+		t.Parallel()
+
+		q := newTenantQuerySet(PostgresDBType).AddField("Name").
+			AddKeySet(KeySet{}.Add("ID", "a").Add("TenantID", "t1")).
+			AddKeySet(KeySet{}.Add("TenantID", "t2").Add("ID", "b"))
+
+		batch, err := q.batch()
+		if err != nil {
+			t.Fatalf("QuerySet.batch() error = %v", err)
+		}
+		got, err := batch.batchStmt()
+		if err != nil {
+			t.Fatalf("QuerySet.batchStmt() error = %v", err)
+		}
+
+		want := `SELECT "tenant_id", "id", "name" FROM "TenantResources" WHERE ("tenant_id" = @batch0key0 AND "id" = @batch0key1) OR ("tenant_id" = @batch1key0 AND "id" = @batch1key1)`
+		if diff := cmp.Diff(want, strings.Join(strings.Fields(got.Sql), " ")); diff != "" {
+			t.Errorf("QuerySet.batchStmt() SQL mismatch (-want +got):\n%s", diff)
+		}
+		wantParams := map[string]any{"batch0key0": "t1", "batch0key1": "a", "batch1key0": "t2", "batch1key1": "b"}
+		if diff := cmp.Diff(wantParams, got.Params); diff != "" {
+			t.Errorf("QuerySet.batchStmt() params mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid key sets", func(t *testing.T) {
+		t.Parallel()
+
+		for _, keySet := range []KeySet{{}, KeySet{}.Add("ID", "a"), KeySet{}.Add("ID", "a").Add("Name", "b")} {
+			if _, err := newTenantQuerySet(PostgresDBType).AddKeySet(keySet).batch(); err == nil {
+				t.Errorf("QuerySet.batch() with key set %v error = nil, want error", keySet.Parts())
+			}
+		}
+		if _, err := newTenantQuerySet(PostgresDBType).batch(); err == nil {
+			t.Errorf("QuerySet.batch() without key sets error = nil, want error")
+		}
+	})
+}
+
+func TestQuerySet_SQLiteBatchRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		keys         []string
+		filters      []Filter
+		want         []*filterResource
+		wantNotFound []KeySet
+	}{
+		{
+			name: "aligned to keys",
+			keys: []string{"c", "a"},
+			want: []*filterResource{{ID: "c", Status: "50% done"}, {ID: "a", Status: "active"}},
+		},
+		{
+			name:         "not found",
+			keys:         []string{"a", "z", "b"},
+			want:         []*filterResource{{ID: "a", Status: "active"}, nil, {ID: "b", Status: "pending review"}},
+			wantNotFound: []KeySet{KeySet{}.Add("ID", "z")},
+		},
+		{
+			name:         "filtered out",
+			keys:         []string{"a", "b"},
+			filters:      []Filter{{Field: "Status", Operator: PrefixOperator, Value: "pend"}},
+			want:         []*filterResource{nil, {ID: "b", Status: "pending review"}},
+			wantNotFound: []KeySet{KeySet{}.Add("ID", "a")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := newSQLiteDB(t)
+			q := newFilterQuerySet(SQLiteDBType).AddField("Status")
+			for _, key := range tt.keys {
+				q.AddKeySet(KeySet{}.Add("ID", key))
+			}
+			for _, f := range tt.filters {
+				q.AddFilter(f)
+			}
+
+			var got []*filterResource
+			notFound, err := q.SQLiteBatchRead(context.Background(), db, &got)
+			if err != nil {
+				t.Fatalf("QuerySet.SQLiteBatchRead() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.SQLiteBatchRead() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantNotFound, notFound, cmp.AllowUnexported(KeySet{})); diff != "" {
+				t.Errorf("QuerySet.SQLiteBatchRead() notFound mismatch (-want +got):\n%s", diff)
+			}
+			if q.Len() != 1 {
+				t.Errorf("QuerySet.SQLiteBatchRead() changed the fields to %v", q.Fields())
+			}
+		})
+	}
+}
+
+func TestQuerySet_SQLiteBatchRead_compositeKey(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLiteDB(t)
+	if _, err := db.Exec(`
+		CREATE TABLE TenantResources ("tenant_id" TEXT, "id" TEXT, "name" TEXT, PRIMARY KEY ("tenant_id", "id"));
+		INSERT INTO TenantResources VALUES ('t1', 'a', 'one'), ('t2', 'a', 'two');
+	`); err != nil {
+		t.Fatalf("sql.DB.Exec() error = %v", err)
+	}
+
+	q := newTenantQuerySet(SQLiteDBType).AddField("Name").
+		AddKeySet(KeySet{}.Add("TenantID", "t2").Add("ID", "a")).
+		AddKeySet(KeySet{}.Add("TenantID", "t1").Add("ID", "b")).
+		AddKeySet(KeySet{}.Add("TenantID", "t1").Add("ID", "a"))
+
+	var got []*tenantResource
+	notFound, err := q.SQLiteBatchRead(context.Background(), db, &got)
+	if err != nil {
+		t.Fatalf("QuerySet.SQLiteBatchRead() error = %v", err)
+	}
+
+	want := []*tenantResource{{TenantID: "t2", ID: "a", Name: "two"}, nil, {TenantID: "t1", ID: "a", Name: "one"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("QuerySet.SQLiteBatchRead() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]KeySet{KeySet{}.Add("TenantID", "t1").Add("ID", "b")}, notFound, cmp.AllowUnexported(KeySet{})); diff != "" {
+		t.Errorf("QuerySet.SQLiteBatchRead() notFound mismatch (-want +got):\n%s", diff)
+	}
+}
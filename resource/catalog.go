@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+)
+
+// Catalog is a JSON serializable view of the resources and permissions recorded in a Collection.
+type Catalog struct {
+	Permissions            map[accesstypes.Permission][]accesstypes.Resource     `json:"permissions"`
+	Resources              []accesstypes.Resource                                `json:"resources"`
+	Tags                   map[accesstypes.Resource][]accesstypes.Tag            `json:"tags"`
+	ImmutableFields        map[accesstypes.Resource][]accesstypes.Tag            `json:"immutableFields"`
+	Scopes                 map[accesstypes.Resource]accesstypes.PermissionScope  `json:"scopes"`
+	PermissionKinds        map[accesstypes.Permission]accesstypes.PermissionKind `json:"permissionKinds"`
+	PermissionDescriptions map[accesstypes.Permission]string                     `json:"permissionDescriptions,omitempty"`
+	ResourceDescriptions   map[accesstypes.Resource]string                       `json:"resourceDescriptions,omitempty"`
+}
+
+// Catalog returns a snapshot of everything recorded in the Collection.
+func (s *Collection) Catalog() *Catalog {
+	permissions := s.List()
+	for perm := range permissions {
+		slices.Sort(permissions[perm])
+	}
+
+	resources := s.Resources()
+
+	scopes := make(map[accesstypes.Resource]accesstypes.PermissionScope, len(resources))
+	for _, res := range resources {
+		scopes[res] = s.Scope(res)
+	}
+
+	return &Catalog{
+		Permissions:            permissions,
+		Resources:              resources,
+		Tags:                   s.tags(),
+		ImmutableFields:        s.immutableFieldList(),
+		Scopes:                 scopes,
+		PermissionKinds:        s.permissionKinds(),
+		PermissionDescriptions: s.PermissionDescriptions(),
+		ResourceDescriptions:   s.ResourceDescriptions(),
+	}
+}
+
+// Handler returns an http.Handler that serves the Collection's Catalog as JSON.
+func (s *Collection) Handler() http.Handler {
+	return httpio.Log(func(w http.ResponseWriter, _ *http.Request) error {
+		return httpio.NewEncoder(w).Ok(s.Catalog())
+	})
+}
+
+func (s *Collection) immutableFieldList() map[accesstypes.Resource][]accesstypes.Tag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	immutableFields := make(map[accesstypes.Resource][]accesstypes.Tag)
+	for _, fieldMap := range s.immutableFields {
+		for res, tags := range fieldMap {
+			for tag := range tags {
+				immutableFields[res] = append(immutableFields[res], tag)
+			}
+			slices.Sort(immutableFields[res])
+		}
+	}
+
+	return immutableFields
+}
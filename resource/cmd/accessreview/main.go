@@ -0,0 +1,85 @@
+// accessreview emits a roles × resources × permissions matrix along with policy findings.
+//
+// Usage:
+//
+//	accessreview -collection collection.json -policy policy.json [-format csv|markdown|json] [-out report.csv]
+//
+// The collection file is a JSON encoded accessreview.Snapshot, typically written by an application
+// built with the collect_resource_permissions build tag or using resource.RuntimeCollection. The policy
+// file is a JSON encoded accesstypes.Policy mapping domain -> role -> allow/deny -> permission -> resources.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cccteam/ccc/resource/accessreview"
+	"github.com/go-playground/errors/v5"
+)
+
+func main() {
+	collectionPath := flag.String("collection", "", "path to the JSON encoded collection snapshot")
+	policyPath := flag.String("policy", "", "path to the JSON encoded role policy")
+	format := flag.String("format", string(accessreview.CSVFormat), "output format: csv, markdown or json")
+	outPath := flag.String("out", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	if err := run(*collectionPath, *policyPath, accessreview.Format(*format), *outPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(collectionPath, policyPath string, format accessreview.Format, outPath string) error {
+	if collectionPath == "" || policyPath == "" {
+		return errors.New("both -collection and -policy are required")
+	}
+
+	collectionFile, err := os.Open(collectionPath)
+	if err != nil {
+		return errors.Wrap(err, "os.Open()")
+	}
+	defer collectionFile.Close()
+
+	snapshot, err := accessreview.LoadSnapshot(collectionFile)
+	if err != nil {
+		return errors.Wrap(err, "accessreview.LoadSnapshot()")
+	}
+
+	policyFile, err := os.Open(policyPath)
+	if err != nil {
+		return errors.Wrap(err, "os.Open()")
+	}
+	defer policyFile.Close()
+
+	policy, err := accessreview.LoadPolicy(policyFile)
+	if err != nil {
+		return errors.Wrap(err, "accessreview.LoadPolicy()")
+	}
+
+	report := accessreview.NewReport(snapshot, policy)
+	if outPath == "" {
+		if err := report.Write(os.Stdout, format); err != nil {
+			return errors.Wrap(err, "accessreview.Report.Write()")
+		}
+
+		return nil
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return errors.Wrap(err, "os.Create()")
+	}
+	defer file.Close()
+
+	if err := report.Write(file, format); err != nil {
+		return errors.Wrap(err, "accessreview.Report.Write()")
+	}
+
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "file.Close()")
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"maps"
 	"slices"
 	"sync"
 
@@ -16,7 +17,7 @@ type (
 )
 
 func AddResources[Resource Resourcer, Request any](s *Collection, scope accesstypes.PermissionScope, rSet *ResourceSet[Resource, Request]) error {
-	if !collectResourcePermissions {
+	if !s.collecting() {
 		return nil
 	}
 
@@ -61,26 +62,60 @@ func AddResources[Resource Resourcer, Request any](s *Collection, scope accessty
 
 	s.immutableFields[scope][res] = rSet.ImmutableFields()
 
+	for tag, description := range rSet.TagDescriptions() {
+		s.resourceDescriptions[res.ResourceWithTag(tag)] = description
+	}
+
 	return nil
 }
 
+// CollectionOption configures a Collection.
+type CollectionOption func(c *Collection)
+
+// RuntimeCollection enables recording of registrations in builds without the
+// collect_resource_permissions build tag.
+func RuntimeCollection() CollectionOption {
+	return func(c *Collection) {
+		c.runtime = true
+	}
+}
+
 type Collection struct {
-	mu              sync.RWMutex
-	tagStore        map[accesstypes.PermissionScope]tagStore
-	resourceStore   map[accesstypes.PermissionScope]resourceStore
-	immutableFields map[accesstypes.PermissionScope]immutableFieldMap
+	mu                     sync.RWMutex
+	runtime                bool
+	tagStore               map[accesstypes.PermissionScope]tagStore
+	resourceStore          map[accesstypes.PermissionScope]resourceStore
+	immutableFields        map[accesstypes.PermissionScope]immutableFieldMap
+	permissionDescriptions map[accesstypes.Permission]string
+	resourceDescriptions   map[accesstypes.Resource]string
+	// loadedPermissionKinds override the kinds in the accesstypes permission registry for a Collection loaded from a Manifest
+	loadedPermissionKinds map[accesstypes.Permission]accesstypes.PermissionKind
 }
 
-func NewCollection() *Collection {
-	if !collectResourcePermissions {
-		return &Collection{}
+func NewCollection(opts ...CollectionOption) *Collection {
+	c := &Collection{}
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	return &Collection{
-		tagStore:        make(map[accesstypes.PermissionScope]tagStore, 2),
-		resourceStore:   make(map[accesstypes.PermissionScope]resourceStore, 2),
-		immutableFields: make(map[accesstypes.PermissionScope]immutableFieldMap, 2),
+	if !c.collecting() {
+		return c
 	}
+
+	c.tagStore = make(map[accesstypes.PermissionScope]tagStore, 2)
+	c.resourceStore = make(map[accesstypes.PermissionScope]resourceStore, 2)
+	c.immutableFields = make(map[accesstypes.PermissionScope]immutableFieldMap, 2)
+	c.permissionDescriptions = make(map[accesstypes.Permission]string)
+	c.loadedPermissionKinds = make(map[accesstypes.Permission]accesstypes.PermissionKind)
+	c.resourceDescriptions = make(map[accesstypes.Resource]string)
+
+	return c
+}
+
+// collecting reports whether registrations are recorded, either because of the
+// collect_resource_permissions build tag or the RuntimeCollection option.
+func (s *Collection) collecting() bool {
+	return collectResourcePermissions || s.runtime
 }
 
 func (s *Collection) AddResource(scope accesstypes.PermissionScope, permission accesstypes.Permission, res accesstypes.Resource) error {
@@ -88,7 +123,7 @@ func (s *Collection) AddResource(scope accesstypes.PermissionScope, permission a
 		return errors.New("cannot register null permission")
 	}
 
-	if !collectResourcePermissions {
+	if !s.collecting() {
 		return nil
 	}
 
@@ -114,7 +149,63 @@ func (s *Collection) addResource(allowDuplicateRegistration bool, scope accessty
 	return nil
 }
 
+// DescribePermission sets a human-readable description for permission, overriding the
+// description registered with accesstypes.RegisterPermission.
+func (s *Collection) DescribePermission(permission accesstypes.Permission, description string) {
+	if !s.collecting() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.permissionDescriptions[permission] = description
+}
+
+// DescribeResource sets a human-readable description for res, which may be a base resource or a tagged resource.
+func (s *Collection) DescribeResource(res accesstypes.Resource, description string) {
+	if !s.collecting() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resourceDescriptions[res] = description
+}
+
+// PermissionDescriptions returns the descriptions of the collected permissions. Permissions without
+// a description set on the Collection fall back to the description in the accesstypes permission registry.
+func (s *Collection) PermissionDescriptions() map[accesstypes.Permission]string {
+	permissions := s.permissions()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	descriptions := make(map[accesstypes.Permission]string, len(permissions))
+	for _, perm := range permissions {
+		if description, ok := s.permissionDescriptions[perm]; ok {
+			descriptions[perm] = description
+		} else if detail, ok := accesstypes.LookupPermission(perm); ok && detail.Description != "" {
+			descriptions[perm] = detail.Description
+		}
+	}
+
+	return descriptions
+}
+
+// ResourceDescriptions returns the descriptions of resources and tagged resources.
+func (s *Collection) ResourceDescriptions() map[accesstypes.Resource]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.resourceDescriptions)
+}
+
 func (s *Collection) IsResourceImmutable(scope accesstypes.PermissionScope, res accesstypes.Resource) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	resource, tag := res.ResourceAndTag()
 	_, ok := s.immutableFields[scope][resource][tag]
 
@@ -143,6 +234,24 @@ func (s *Collection) permissions() []accesstypes.Permission {
 	return slices.Compact(permissions)
 }
 
+func (s *Collection) permissionKinds() map[accesstypes.Permission]accesstypes.PermissionKind {
+	permissions := s.permissions()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	kinds := make(map[accesstypes.Permission]accesstypes.PermissionKind, len(permissions))
+	for _, perm := range permissions {
+		if kind, ok := s.loadedPermissionKinds[perm]; ok {
+			kinds[perm] = kind
+		} else {
+			kinds[perm] = perm.Kind()
+		}
+	}
+
+	return kinds
+}
+
 func (s *Collection) Resources() []accesstypes.Resource {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -221,6 +330,9 @@ func (s *Collection) resourcePermissions() permissionMap {
 }
 
 func (s *Collection) domains() []accesstypes.PermissionScope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	domains := make([]accesstypes.PermissionScope, 0, len(s.resourceStore))
 	for domain := range s.resourceStore {
 		domains = append(domains, domain)
@@ -277,10 +389,13 @@ func (s *Collection) Scope(resource accesstypes.Resource) accesstypes.Permission
 
 func (c *Collection) TypescriptData() TypescriptData {
 	return TypescriptData{
-		Permissions:         c.permissions(),
-		Resources:           c.Resources(),
-		ResourceTags:        c.tags(),
-		ResourcePermissions: c.resourcePermissions(),
-		Domains:             c.domains(),
+		Permissions:            c.permissions(),
+		PermissionKinds:        c.permissionKinds(),
+		PermissionDescriptions: c.PermissionDescriptions(),
+		ResourceDescriptions:   c.ResourceDescriptions(),
+		Resources:              c.Resources(),
+		ResourceTags:           c.tags(),
+		ResourcePermissions:    c.resourcePermissions(),
+		Domains:                c.domains(),
 	}
 }
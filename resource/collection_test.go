@@ -0,0 +1,66 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+type catalogRequest struct {
+	Field1 string `json:"field1" perm:"Update" description:"The first field"`
+	Field2 string `json:"field2" perm:"Immutable"`
+}
+
+func TestCollection_Catalog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []CollectionOption
+		want    *Catalog
+		collect bool
+	}{
+		{
+			name:    "runtime collection",
+			opts:    []CollectionOption{RuntimeCollection()},
+			collect: true,
+			want: &Catalog{
+				Permissions: map[accesstypes.Permission][]accesstypes.Resource{
+					accesstypes.Update: {"AResources", "AResources.field1", "AResources.field2"},
+				},
+				Resources:              []accesstypes.Resource{"AResources"},
+				Tags:                   map[accesstypes.Resource][]accesstypes.Tag{"AResources": {"field1", "field2"}},
+				ImmutableFields:        map[accesstypes.Resource][]accesstypes.Tag{"AResources": {"field2"}},
+				Scopes:                 map[accesstypes.Resource]accesstypes.PermissionScope{"AResources": accesstypes.GlobalPermissionScope},
+				PermissionKinds:        map[accesstypes.Permission]accesstypes.PermissionKind{accesstypes.Update: accesstypes.MutatingPermissionKind},
+				PermissionDescriptions: map[accesstypes.Permission]string{accesstypes.Update: "Update an existing resource"},
+				ResourceDescriptions:   map[accesstypes.Resource]string{"AResources": "A resources", "AResources.field1": "The first field"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewCollection(tt.opts...)
+
+			rSet, err := NewResourceSet[AResource, catalogRequest]()
+			if err != nil {
+				t.Fatalf("NewResourceSet() error = %v", err)
+			}
+			if err := AddResources(c, accesstypes.GlobalPermissionScope, rSet); err != nil {
+				t.Fatalf("AddResources() error = %v", err)
+			}
+			c.DescribeResource("AResources", "A resources")
+
+			if got := c.IsResourceImmutable(accesstypes.GlobalPermissionScope, "AResources.field2"); got != tt.collect {
+				t.Errorf("Collection.IsResourceImmutable() = %v, want %v", got, tt.collect)
+			}
+
+			if diff := cmp.Diff(tt.want, c.Catalog()); diff != "" {
+				t.Errorf("Collection.Catalog() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
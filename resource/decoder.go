@@ -105,10 +105,10 @@ func (d *DecoderWithPermissionChecker[Resource, Request]) Decode(request *http.R
 func (d *DecoderWithPermissionChecker[Resource, Request]) DecodeOperation(oper *Operation) (*PatchSet[Resource], error) {
 	if oper.Type == OperationDelete {
 		ctx, user, domain := oper.Req.Context(), d.userFromReq(oper.Req), d.domainFromReq(oper.Req)
-		if ok, missing, err := d.enforcer.RequireResources(ctx, user, domain, accesstypes.Delete, d.resourceSet.BaseResource()); err != nil {
-			return nil, errors.Wrap(err, "enforcer.RequireResource()")
+		if ok, missing, subject, err := requireResources(ctx, d.enforcer, user, domain, accesstypes.Delete, d.resourceSet.BaseResource()); err != nil {
+			return nil, errors.Wrap(err, "requireResources()")
 		} else if !ok {
-			return nil, httpio.NewForbiddenMessagef("user %s does not have %s on %s", d.userFromReq(oper.Req), accesstypes.Delete, missing)
+			return nil, httpio.NewForbiddenMessagef("%s does not have %s on %s", subject, accesstypes.Delete, missing)
 		}
 
 		return nil, nil
@@ -213,10 +213,10 @@ func checkPermissions[Resource Resourcer, Request any](
 		}
 	}
 
-	if ok, missing, err := enforcer.RequireResources(ctx, user, domain, perm, resources...); err != nil {
-		return errors.Wrap(err, "enforcer.RequireResource()")
+	if ok, missing, subject, err := requireResources(ctx, enforcer, user, domain, perm, resources...); err != nil {
+		return errors.Wrap(err, "requireResources()")
 	} else if !ok {
-		return httpio.NewForbiddenMessagef("user %s does not have %s on %s", user, perm, missing)
+		return httpio.NewForbiddenMessagef("%s does not have %s on %s", subject, perm, missing)
 	}
 
 	return nil
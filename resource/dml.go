@@ -5,6 +5,7 @@ type DBType string
 const (
 	SpannerDBType  DBType = "spanner"
 	PostgresDBType DBType = "postgres"
+	SQLiteDBType   DBType = "sqlite"
 )
 
 type (
@@ -4,15 +4,34 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
 	"github.com/cccteam/session/sessioninfo"
 )
 
+// UserEvent returns the event source for changes made by the session user.
+// When ctx carries an ActingAs, both the impersonator and the effective user are recorded, and when
+// ctx carries a ServiceAccountInfo, the service account is recorded.
 func UserEvent(ctx context.Context) string {
+	if info, ok := ServiceAccountFromCtx(ctx); ok {
+		return ServiceAccountEvent(info.ServiceAccount, info.ID)
+	}
+
+	if actingAs, ok := ActingAsFromCtx(ctx); ok {
+		return fmt.Sprintf("%s (%s) acting as %s (%s)", actingAs.Impersonator, actingAs.ImpersonatorID, actingAs.User, actingAs.UserID)
+	}
+
 	user := sessioninfo.FromCtx(ctx)
 
 	return fmt.Sprintf("%s (%s)", user.Username, user.ID)
 }
 
+// ServiceAccountEvent returns the event source for changes made by a service account,
+// distinguishing them from changes made by a session user.
+func ServiceAccountEvent(serviceAccount accesstypes.ServiceAccount, id ccc.UUID) string {
+	return fmt.Sprintf("ServiceAccount %s (%s)", serviceAccount, id)
+}
+
 func ProcessEvent(processName string) string {
 	return fmt.Sprintf("Process %s", processName)
 }
@@ -0,0 +1,267 @@
+package resource
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+// Reference is the resource referenced by a foreign key field. It is declared on a request field with
+// the references struct tag:
+//
+//	CreatedBy ccc.UUID `json:"createdBy" references:"Users.Id,text=Name,embed=Id|Name|Email"`
+//
+// where Users is the referenced resource, Id the referenced column, and the optional text and embed
+// options name the columns of the referenced resource used as the Link text and embedded in the response.
+// The QueryDecoder only expands text and embedded columns of resources added with AddReferencedResource.
+type Reference struct {
+	Resource accesstypes.Resource
+	Column   string
+	Text     string
+	Embed    []string
+}
+
+// Expansion selects the resource referenced by Field.
+type Expansion struct {
+	Field     accesstypes.Field
+	Reference Reference
+}
+
+// Expand joins the resource referenced by field to the query. A Link to the referenced row is returned as JSON
+// in a column named after the field's column with a Link suffix, and when ref.Embed is set, the embedded columns
+// are returned as a JSON object in a column with an Expanded suffix. The destination struct needs fields such as
+//
+//	CreatedByLink     resource.NullLink `spanner:"CreatedByLink"`
+//	CreatedByExpanded spanner.NullJSON  `spanner:"CreatedByExpanded"`
+//
+// The Link is null when the field is null or the referenced row does not exist.
+func (q *QuerySet[Resource]) Expand(field accesstypes.Field, ref Reference) *QuerySet[Resource] {
+	for i := range q.expansions {
+		if q.expansions[i].Field == field {
+			q.expansions[i].Reference = ref
+
+			return q
+		}
+	}
+	q.expansions = append(q.expansions, Expansion{Field: field, Reference: ref})
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Expansions() []Expansion {
+	return q.expansions
+}
+
+// expansionColumns renders the Link and embedded object columns for each expansion, using a correlated
+// subquery on the referenced resource so that the other clauses of the statement are unaffected.
+func (q *QuerySet[Resource]) expansionColumns() (string, error) {
+	if len(q.expansions) == 0 {
+		return "", nil
+	}
+
+	var columns []string
+	for i, e := range q.expansions {
+		c, ok := q.rMeta.fieldMap[e.Field]
+		if !ok {
+			return "", errors.Newf("field %s not found in struct", e.Field)
+		}
+
+		alias := fmt.Sprintf("expand%d", i)
+		ident := func(name string) (string, error) {
+			column, err := quoteIdent(q.rMeta.dbType, name)
+			if err != nil {
+				return "", err
+			}
+
+			return alias + "." + column, nil
+		}
+
+		key, err := ident(e.Reference.Column)
+		if err != nil {
+			return "", err
+		}
+		column, err := quoteIdent(q.rMeta.dbType, c.tag)
+		if err != nil {
+			return "", err
+		}
+		referenced, err := quoteIdent(q.rMeta.dbType, string(e.Reference.Resource))
+		if err != nil {
+			return "", err
+		}
+		table, err := q.table()
+		if err != nil {
+			return "", err
+		}
+		from := fmt.Sprintf("FROM %s AS %s WHERE %s = %s.%s", referenced, alias, key, table, column)
+
+		text := "''"
+		if e.Reference.Text != "" {
+			if text, err = ident(e.Reference.Text); err != nil {
+				return "", err
+			}
+		}
+
+		linkAlias, err := quoteIdent(q.rMeta.dbType, c.tag+"Link")
+		if err != nil {
+			return "", err
+		}
+
+		switch q.rMeta.dbType {
+		case SpannerDBType:
+			columns = append(columns, fmt.Sprintf("(SELECT TO_JSON_STRING(STRUCT(CAST(%s AS STRING) AS id, '%s' AS resource, %s AS text)) %s) AS %s", key, e.Reference.Resource, text, from, linkAlias))
+		case PostgresDBType:
+			columns = append(columns, fmt.Sprintf("(SELECT json_build_object('id', %s, 'resource', '%s', 'text', %s)::text %s) AS %s", key, e.Reference.Resource, text, from, linkAlias))
+		case SQLiteDBType:
+			columns = append(columns, fmt.Sprintf("(SELECT json_object('id', %s, 'resource', '%s', 'text', %s) %s) AS %s", key, e.Reference.Resource, text, from, linkAlias))
+		}
+
+		if len(e.Reference.Embed) == 0 {
+			continue
+		}
+
+		fields := make([]string, 0, len(e.Reference.Embed))
+		for _, name := range e.Reference.Embed {
+			value, err := ident(name)
+			if err != nil {
+				return "", err
+			}
+
+			switch q.rMeta.dbType {
+			case SpannerDBType:
+				fields = append(fields, fmt.Sprintf("%s AS %s", value, name))
+			default:
+				fields = append(fields, fmt.Sprintf("'%s', %s", name, value))
+			}
+		}
+
+		expandedAlias, err := quoteIdent(q.rMeta.dbType, c.tag+"Expanded")
+		if err != nil {
+			return "", err
+		}
+
+		switch q.rMeta.dbType {
+		case SpannerDBType:
+			columns = append(columns, fmt.Sprintf("(SELECT TO_JSON(STRUCT(%s)) %s) AS %s", strings.Join(fields, ", "), from, expandedAlias))
+		case PostgresDBType:
+			columns = append(columns, fmt.Sprintf("(SELECT json_build_object(%s) %s) AS %s", strings.Join(fields, ", "), from, expandedAlias))
+		case SQLiteDBType:
+			columns = append(columns, fmt.Sprintf("(SELECT json_object(%s) %s) AS %s", strings.Join(fields, ", "), from, expandedAlias))
+		}
+	}
+
+	return ", " + strings.Join(columns, ", "), nil
+}
+
+// referencesFromTags returns the references declared with the references struct tag on the fields of t.
+func referencesFromTags(t reflect.Type) (map[accesstypes.Field]Reference, error) {
+	var references map[accesstypes.Field]Reference
+	for _, field := range reflect.VisibleFields(t) {
+		tag, ok := field.Tag.Lookup("references")
+		if !ok {
+			continue
+		}
+
+		ref, err := parseReference(tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", field.Name)
+		}
+
+		if references == nil {
+			references = make(map[accesstypes.Field]Reference)
+		}
+		references[accesstypes.Field(field.Name)] = ref
+	}
+
+	return references, nil
+}
+
+// parseReference parses a references struct tag of the form Resource.Column[,text=Column][,embed=Column|Column].
+func parseReference(tag string) (Reference, error) {
+	target, options, _ := strings.Cut(tag, ",")
+
+	resource, column, found := strings.Cut(target, ".")
+	if !found || !isIdent(resource) || !isIdent(column) {
+		return Reference{}, errors.Newf("invalid references tag %q, expected Resource.Column", tag)
+	}
+	ref := Reference{Resource: accesstypes.Resource(resource), Column: column}
+
+	if options == "" {
+		return ref, nil
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		name, value, _ := strings.Cut(option, "=")
+		switch name {
+		case "text":
+			if !isIdent(value) {
+				return Reference{}, errors.Newf("invalid text column %q in references tag %q", value, tag)
+			}
+			ref.Text = value
+		case "embed":
+			for _, column := range strings.Split(value, "|") {
+				if !isIdent(column) {
+					return Reference{}, errors.Newf("invalid embed column %q in references tag %q", column, tag)
+				}
+				ref.Embed = append(ref.Embed, column)
+			}
+		default:
+			return Reference{}, errors.Newf("unknown option %q in references tag %q", name, tag)
+		}
+	}
+
+	return ref, nil
+}
+
+// isIdent reports whether s can be used unquoted as a table or column name.
+func isIdent(s string) bool {
+	if s == "" || !isParamStart(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		if !isParamChar(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseExpandParam parses the expand query parameter, a comma separated list of the json names of
+// request fields declared with the references struct tag. For example:
+//
+//	?expand=createdBy,assignee
+func parseExpandParam(fieldMapper *FieldMapper, references map[accesstypes.Field]Reference, queryParams url.Values) ([]Expansion, error) {
+	param := queryParams.Get("expand")
+	if param == "" {
+		return nil, nil
+	}
+
+	var expansions []Expansion
+	for _, name := range strings.Split(param, ",") {
+		field, found := fieldMapper.StructFieldName(name)
+		if !found {
+			return nil, httpio.NewBadRequestMessagef("unknown expand field: %s", name)
+		}
+
+		ref, ok := references[field]
+		if !ok {
+			return nil, httpio.NewBadRequestMessagef("field %s can not be expanded", name)
+		}
+
+		for _, e := range expansions {
+			if e.Field == field {
+				return nil, httpio.NewBadRequestMessagef("expand field %s is repeated", name)
+			}
+		}
+
+		expansions = append(expansions, Expansion{Field: field, Reference: ref})
+	}
+
+	return expansions, nil
+}
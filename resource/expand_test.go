@@ -0,0 +1,209 @@
+package resource
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+type taskResource struct {
+	ID            string   `spanner:"Id"              sqlite:"id"               query:"primaryKey"`
+	OwnerID       string   `spanner:"OwnerId"         sqlite:"owner_id"`
+	OwnerIDLink   NullLink `spanner:"OwnerIdLink"     sqlite:"owner_idLink"`
+	OwnerExpanded *string  `spanner:"OwnerIdExpanded" sqlite:"owner_idExpanded"`
+}
+
+func (taskResource) Resource() accesstypes.Resource {
+	return "Tasks"
+}
+
+func (taskResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+type taskRequest struct {
+	ID      string `json:"id"`
+	OwnerID string `json:"ownerId" references:"Users.Id,text=Name"`
+}
+
+func newTaskQuerySet(dbType DBType) *QuerySet[taskResource] {
+	return NewQuerySet(&ResourceMetadata[taskResource]{
+		fieldMap:    structTags(reflect.TypeFor[taskResource](), string(dbType)),
+		primaryKeys: primaryKeyFields(reflect.TypeFor[taskResource]()),
+		dbType:      dbType,
+	})
+}
+
+func Test_parseReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tag     string
+		want    Reference
+		wantErr bool
+	}{
+		{name: "column only", tag: "Users.Id", want: Reference{Resource: "Users", Column: "Id"}},
+		{
+			name: "text and embed",
+			tag:  "Users.Id,text=Name,embed=Id|Name|Email",
+			want: Reference{Resource: "Users", Column: "Id", Text: "Name", Embed: []string{"Id", "Name", "Email"}},
+		},
+		{name: "missing column", tag: "Users", wantErr: true},
+		{name: "invalid column", tag: "Users.Id; DROP", wantErr: true},
+		{name: "empty text", tag: "Users.Id,text=", wantErr: true},
+		{name: "invalid embed", tag: "Users.Id,embed=Id|", wantErr: true},
+		{name: "unknown option", tag: "Users.Id,join=left", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseReference(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseReference() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseExpandParam(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := NewFieldMapper(taskRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+	references, err := referencesFromTags(reflect.TypeFor[taskRequest]())
+	if err != nil {
+		t.Fatalf("referencesFromTags() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []Expansion
+		wantErr bool
+	}{
+		{name: "no expand", query: url.Values{}},
+		{
+			name:  "expand",
+			query: url.Values{"expand": {"ownerId"}},
+			want:  []Expansion{{Field: "OwnerID", Reference: Reference{Resource: "Users", Column: "Id", Text: "Name"}}},
+		},
+		{name: "unknown field", query: url.Values{"expand": {"nope"}}, wantErr: true},
+		{name: "field without reference", query: url.Values{"expand": {"id"}}, wantErr: true},
+		{name: "repeated field", query: url.Values{"expand": {"ownerId,ownerId"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseExpandParam(mapper, references, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExpandParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseExpandParam() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_expansionColumns(t *testing.T) {
+	t.Parallel()
+
+	ref := Reference{Resource: "Users", Column: "Id", Text: "Name", Embed: []string{"Id", "Email"}}
+
+	tests := []struct {
+		name   string
+		dbType DBType
+		ref    Reference
+		want   string
+	}{
+		{
+			name:   "spanner link",
+			dbType: SpannerDBType,
+			ref:    Reference{Resource: "Users", Column: "Id"},
+			want: `, (SELECT TO_JSON_STRING(STRUCT(CAST(expand0.Id AS STRING) AS id, 'Users' AS resource, '' AS text)) ` +
+				`FROM Users AS expand0 WHERE expand0.Id = Tasks.OwnerId) AS OwnerIdLink`,
+		},
+		{
+			name:   "spanner embed",
+			dbType: SpannerDBType,
+			ref:    ref,
+			want: `, (SELECT TO_JSON_STRING(STRUCT(CAST(expand0.Id AS STRING) AS id, 'Users' AS resource, expand0.Name AS text)) ` +
+				`FROM Users AS expand0 WHERE expand0.Id = Tasks.OwnerId) AS OwnerIdLink, ` +
+				`(SELECT TO_JSON(STRUCT(expand0.Id AS Id, expand0.Email AS Email)) ` +
+				`FROM Users AS expand0 WHERE expand0.Id = Tasks.OwnerId) AS OwnerIdExpanded`,
+		},
+		{
+			name:   "sqlite embed",
+			dbType: SQLiteDBType,
+			ref:    ref,
+			want: `, (SELECT json_object('id', expand0."Id", 'resource', 'Users', 'text', expand0."Name") ` +
+				`FROM "Users" AS expand0 WHERE expand0."Id" = "Tasks"."owner_id") AS "owner_idLink", ` +
+				`(SELECT json_object('Id', expand0."Id", 'Email', expand0."Email") ` +
+				`FROM "Users" AS expand0 WHERE expand0."Id" = "Tasks"."owner_id") AS "owner_idExpanded"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newTaskQuerySet(tt.dbType).Expand("OwnerID", tt.ref).expansionColumns()
+			if err != nil {
+				t.Fatalf("QuerySet.expansionColumns() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.expansionColumns() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_SQLiteList_expand(t *testing.T) {
+	t.Parallel()
+
+	owner := ccc.Must(ccc.NewUUID())
+	db := newSQLiteDB(t)
+	if _, err := db.Exec(`
+		CREATE TABLE Users ("Id" TEXT PRIMARY KEY, "Name" TEXT, "Email" TEXT);
+		CREATE TABLE Tasks ("id" TEXT PRIMARY KEY, "owner_id" TEXT);
+		INSERT INTO Users VALUES (?, 'Ada', 'ada@example.com');
+		INSERT INTO Tasks VALUES ('a', ?), ('b', 'missing');
+	`, owner.String(), owner.String()); err != nil {
+		t.Fatalf("sql.DB.Exec() error = %v", err)
+	}
+
+	q := newTaskQuerySet(SQLiteDBType).AddField("ID").AddField("OwnerID").
+		Expand("OwnerID", Reference{Resource: "Users", Column: "Id", Text: "Name", Embed: []string{"Email"}}).
+		OrderBy(SortField{Field: "ID"})
+
+	var got []taskResource
+	if err := q.SQLiteList(context.Background(), db, &got); err != nil {
+		t.Fatalf("QuerySet.SQLiteList() error = %v", err)
+	}
+
+	embedded := `{"Email":"ada@example.com"}`
+	want := []taskResource{
+		{
+			ID:            "a",
+			OwnerID:       owner.String(),
+			OwnerIDLink:   NullLink{Link: Link{ID: owner, Resource: "Users", Text: "Ada"}, Valid: true},
+			OwnerExpanded: &embedded,
+		},
+		{ID: "b", OwnerID: "missing"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("QuerySet.SQLiteList() mismatch (-want +got):\n%s", diff)
+	}
+}
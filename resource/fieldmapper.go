@@ -10,17 +10,19 @@ import (
 
 type FieldMapper struct {
 	jsonTagToFields map[string]accesstypes.Field
+	fieldToJSONTags map[accesstypes.Field]string
 	fields          []accesstypes.Field
 }
 
 func NewFieldMapper(v any) (*FieldMapper, error) {
-	jsonTagToFields, fields, err := tagToFieldMap(v)
+	jsonTagToFields, fieldToJSONTags, fields, err := tagToFieldMap(v)
 	if err != nil {
 		return nil, err
 	}
 
 	return &FieldMapper{
 		jsonTagToFields: jsonTagToFields,
+		fieldToJSONTags: fieldToJSONTags,
 		fields:          fields,
 	}, nil
 }
@@ -31,6 +33,13 @@ func (f *FieldMapper) StructFieldName(tag string) (accesstypes.Field, bool) {
 	return fieldName, ok
 }
 
+// JSONTag returns the json name of field.
+func (f *FieldMapper) JSONTag(field accesstypes.Field) (string, bool) {
+	tag, ok := f.fieldToJSONTags[field]
+
+	return tag, ok
+}
+
 func (f *FieldMapper) Len() int {
 	return len(f.jsonTagToFields)
 }
@@ -39,29 +48,31 @@ func (f *FieldMapper) Fields() []accesstypes.Field {
 	return f.fields
 }
 
-func tagToFieldMap(v any) (map[string]accesstypes.Field, []accesstypes.Field, error) {
+func tagToFieldMap(v any) (map[string]accesstypes.Field, map[accesstypes.Field]string, []accesstypes.Field, error) {
 	vType := reflect.TypeOf(v)
 
 	if vType.Kind() == reflect.Ptr {
 		vType = vType.Elem()
 	}
 	if vType.Kind() != reflect.Struct {
-		return nil, nil, errors.Newf("argument v must be a struct, received %v", vType.Kind())
+		return nil, nil, nil, errors.Newf("argument v must be a struct, received %v", vType.Kind())
 	}
 
 	tfMap := make(map[string]accesstypes.Field)
+	ftMap := make(map[accesstypes.Field]string)
 	fields := make([]accesstypes.Field, 0, vType.NumField())
 	for _, field := range reflect.VisibleFields(vType) {
 		tag := field.Tag.Get("json")
 		if tag == "" {
 			if _, ok := tfMap[field.Name]; ok {
-				return nil, nil, errors.Newf("field name %s collides with another field tag", field.Name)
+				return nil, nil, nil, errors.Newf("field name %s collides with another field tag", field.Name)
 			}
 			tfMap[field.Name] = accesstypes.Field(field.Name)
+			ftMap[accesstypes.Field(field.Name)] = field.Name
 			fields = append(fields, accesstypes.Field(field.Name))
 			if lowerFieldName := strings.ToLower(field.Name); lowerFieldName != field.Name {
 				if _, ok := tfMap[lowerFieldName]; ok {
-					return nil, nil, errors.Newf("field name %s has multiple matches", field.Name)
+					return nil, nil, nil, errors.Newf("field name %s has multiple matches", field.Name)
 				}
 				tfMap[lowerFieldName] = accesstypes.Field(field.Name)
 			}
@@ -78,11 +89,12 @@ func tagToFieldMap(v any) (map[string]accesstypes.Field, []accesstypes.Field, er
 		}
 
 		if _, ok := tfMap[tag]; ok {
-			return nil, nil, errors.Newf("tag %s has multiple matches", tag)
+			return nil, nil, nil, errors.Newf("tag %s has multiple matches", tag)
 		}
 		tfMap[tag] = accesstypes.Field(field.Name)
+		ftMap[accesstypes.Field(field.Name)] = tag
 		fields = append(fields, accesstypes.Field(field.Name))
 	}
 
-	return tfMap, fields, nil
+	return tfMap, ftMap, fields, nil
 }
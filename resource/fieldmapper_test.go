@@ -32,6 +32,10 @@ func TestNewFieldMapper(t *testing.T) {
 					"field1": "Field1",
 					"field2": "Field2",
 				},
+				fieldToJSONTags: map[accesstypes.Field]string{
+					"Field1": "field1",
+					"Field2": "field2",
+				},
 				fields: []accesstypes.Field{
 					"Field1",
 					"Field2",
@@ -113,6 +117,65 @@ func TestFieldMapper_StructFieldName(t *testing.T) {
 	}
 }
 
+func TestFieldMapper_JSONTag(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		fieldToJSONTags map[accesstypes.Field]string
+	}
+	type args struct {
+		field accesstypes.Field
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   string
+		want1  bool
+	}{
+		{
+			name: "JSONTag",
+			fields: fields{
+				fieldToJSONTags: map[accesstypes.Field]string{
+					"Field1": "field1",
+					"Field2": "field2",
+				},
+			},
+			args: args{
+				field: "Field1",
+			},
+			want:  "field1",
+			want1: true,
+		},
+		{
+			name: "JSONTag unknown field",
+			fields: fields{
+				fieldToJSONTags: map[accesstypes.Field]string{
+					"Field1": "field1",
+				},
+			},
+			args: args{
+				field: "Field2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := &FieldMapper{
+				fieldToJSONTags: tt.fields.fieldToJSONTags,
+			}
+			got, got1 := f.JSONTag(tt.args.field)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FieldMapper.JSONTag() mismatch (-want +got):\n%s", diff)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("FieldMapper.JSONTag() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
 func TestFieldMapper_Len(t *testing.T) {
 	t.Parallel()
 
@@ -205,6 +268,7 @@ func Test_tagToFieldMap(t *testing.T) {
 		name       string
 		args       args
 		want       map[string]accesstypes.Field
+		wantTags   map[accesstypes.Field]string
 		wantFields []accesstypes.Field
 		wantErr    bool
 	}{
@@ -220,6 +284,10 @@ func Test_tagToFieldMap(t *testing.T) {
 				"field1": "Field1",
 				"field2": "Field2",
 			},
+			wantTags: map[accesstypes.Field]string{
+				"Field1": "field1",
+				"Field2": "field2",
+			},
 			wantFields: []accesstypes.Field{
 				"Field1",
 				"Field2",
@@ -238,6 +306,10 @@ func Test_tagToFieldMap(t *testing.T) {
 				"field1": "Field1",
 				"field2": "Field2",
 			},
+			wantTags: map[accesstypes.Field]string{
+				"Field1": "field1",
+				"Field2": "field2",
+			},
 			wantFields: []accesstypes.Field{
 				"Field1",
 				"Field2",
@@ -255,6 +327,9 @@ func Test_tagToFieldMap(t *testing.T) {
 			want: map[string]accesstypes.Field{
 				"field2": "Field2",
 			},
+			wantTags: map[accesstypes.Field]string{
+				"Field2": "field2",
+			},
 			wantFields: []accesstypes.Field{
 				"Field2",
 			},
@@ -273,6 +348,10 @@ func Test_tagToFieldMap(t *testing.T) {
 				"fieldtag1": "FieldTag1",
 				"fieldTag2": "FieldTag2",
 			},
+			wantTags: map[accesstypes.Field]string{
+				"FieldTag1": "FieldTag1",
+				"FieldTag2": "fieldTag2",
+			},
 			wantFields: []accesstypes.Field{
 				"FieldTag1",
 				"FieldTag2",
@@ -291,6 +370,10 @@ func Test_tagToFieldMap(t *testing.T) {
 				"field1": "Field1",
 				"field2": "Field2",
 			},
+			wantTags: map[accesstypes.Field]string{
+				"Field1": "field1",
+				"Field2": "field2",
+			},
 			wantFields: []accesstypes.Field{
 				"Field1",
 				"Field2",
@@ -310,7 +393,7 @@ func Test_tagToFieldMap(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, gotFileds, err := tagToFieldMap(tt.args.v)
+			got, gotTags, gotFileds, err := tagToFieldMap(tt.args.v)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("tagToFieldMap() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -318,6 +401,9 @@ func Test_tagToFieldMap(t *testing.T) {
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("tagToFieldMap() mismatch (-want +got):\n%s", diff)
 			}
+			if diff := cmp.Diff(tt.wantTags, gotTags); diff != "" {
+				t.Errorf("tagToFieldMap() tags mismatch (-want +got):\n%s", diff)
+			}
 			if diff := cmp.Diff(tt.wantFields, gotFileds); diff != "" {
 				t.Errorf("tagToFieldMap() fields mismatch (-want +got):\n%s", diff)
 			}
@@ -0,0 +1,269 @@
+package resource
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+// FilterOperator is a comparison supported by the filter query parameter.
+type FilterOperator string
+
+const (
+	EqualOperator              FilterOperator = "eq"
+	NotEqualOperator           FilterOperator = "ne"
+	LessThanOperator           FilterOperator = "lt"
+	LessThanOrEqualOperator    FilterOperator = "lte"
+	GreaterThanOperator        FilterOperator = "gt"
+	GreaterThanOrEqualOperator FilterOperator = "gte"
+	InOperator                 FilterOperator = "in"
+	IsNullOperator             FilterOperator = "isnull"
+	PrefixOperator             FilterOperator = "prefix"
+)
+
+// Filter is a single condition applied to a list query.
+//
+// Value holds a value of the resource field's type. For InOperator it is a slice of that type,
+// and for IsNullOperator it is a bool reporting whether the field must be null.
+type Filter struct {
+	Field    accesstypes.Field
+	Operator FilterOperator
+	Value    any
+}
+
+// parseFilterParam parses the filter query parameters into Filters.
+//
+// Each filter parameter has the form field:operator:value, where field is the json name of a
+// request field. Multiple filters are combined with AND by repeating the parameter, and values
+// for the in operator are separated by commas. For example:
+//
+//	?filter=status:in:active,pending&filter=createdAt:gte:2024-01-01T00:00:00Z&filter=deletedAt:isnull:true
+func parseFilterParam(fieldMapper *FieldMapper, resourceType reflect.Type, fieldMap map[accesstypes.Field]cacheEntry, queryParams url.Values) ([]Filter, error) {
+	params := queryParams["filter"]
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	filters := make([]Filter, 0, len(params))
+	for _, param := range params {
+		name, rest, found := strings.Cut(param, ":")
+		if !found {
+			return nil, httpio.NewBadRequestMessagef("invalid filter %q, expected field:operator:value", param)
+		}
+		op, val, found := strings.Cut(rest, ":")
+		if !found {
+			return nil, httpio.NewBadRequestMessagef("invalid filter %q, expected field:operator:value", param)
+		}
+
+		field, found := fieldMapper.StructFieldName(name)
+		if !found {
+			return nil, httpio.NewBadRequestMessagef("unknown filter field: %s", name)
+		}
+
+		c, ok := fieldMap[field]
+		if !ok {
+			return nil, httpio.NewBadRequestMessagef("field %s can not be filtered", name)
+		}
+		typ := resourceType.Field(c.index).Type
+
+		value, err := filterValue(FilterOperator(op), typ, val)
+		if err != nil {
+			return nil, httpio.NewBadRequestMessagef("invalid filter on %s: %s", name, err)
+		}
+
+		filters = append(filters, Filter{Field: field, Operator: FilterOperator(op), Value: value})
+	}
+
+	return filters, nil
+}
+
+func filterValue(op FilterOperator, typ reflect.Type, val string) (any, error) {
+	switch op {
+	case EqualOperator, NotEqualOperator, LessThanOperator, LessThanOrEqualOperator, GreaterThanOperator, GreaterThanOrEqualOperator:
+		return convertFilterValue(typ, val)
+	case PrefixOperator:
+		if filterType(typ).Kind() != reflect.String {
+			return nil, errors.Newf("operator %s requires a string field", op)
+		}
+
+		return val, nil
+	case InOperator:
+		elemType := filterType(typ)
+		vals := strings.Split(val, ",")
+		values := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(vals))
+		for _, v := range vals {
+			value, err := convertFilterValue(elemType, v)
+			if err != nil {
+				return nil, err
+			}
+			values = reflect.Append(values, reflect.ValueOf(value))
+		}
+
+		return values.Interface(), nil
+	case IsNullOperator:
+		isNull, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, errors.Newf("operator %s requires true or false, got %q", op, val)
+		}
+
+		return isNull, nil
+	default:
+		return nil, errors.Newf("unknown operator %q", op)
+	}
+}
+
+// convertFilterValue converts val into a value of typ. Pointer types are converted to their element type,
+// and nullable Spanner types to the type of their value.
+func convertFilterValue(typ reflect.Type, val string) (any, error) {
+	typ = filterType(typ)
+
+	ptr := reflect.New(typ)
+	if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(val)); err != nil {
+			return nil, errors.Newf("invalid value %q", val)
+		}
+
+		return ptr.Elem().Interface(), nil
+	}
+
+	v := ptr.Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, errors.Newf("invalid bool %q", val)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, typ.Bits())
+		if err != nil {
+			return nil, errors.Newf("invalid integer %q", val)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(val, 10, typ.Bits())
+		if err != nil {
+			return nil, errors.Newf("invalid unsigned integer %q", val)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, typ.Bits())
+		if err != nil {
+			return nil, errors.Newf("invalid number %q", val)
+		}
+		v.SetFloat(f)
+	default:
+		return nil, errors.Newf("unsupported field type %s", typ)
+	}
+
+	return v.Interface(), nil
+}
+
+// nullableValueTypes maps the nullable Spanner types to the type of their value. A null value is
+// filtered with IsNullOperator, so filter values are always of the value type.
+var nullableValueTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeFor[spanner.NullString]():  reflect.TypeFor[string](),
+	reflect.TypeFor[spanner.NullInt64]():   reflect.TypeFor[int64](),
+	reflect.TypeFor[spanner.NullFloat64](): reflect.TypeFor[float64](),
+	reflect.TypeFor[spanner.NullFloat32](): reflect.TypeFor[float32](),
+	reflect.TypeFor[spanner.NullBool]():    reflect.TypeFor[bool](),
+	reflect.TypeFor[spanner.NullTime]():    reflect.TypeFor[time.Time](),
+	reflect.TypeFor[spanner.NullDate]():    reflect.TypeFor[civil.Date](),
+	reflect.TypeFor[spanner.NullNumeric](): reflect.TypeFor[big.Rat](),
+}
+
+// filterType returns the type of the values used to filter a field of typ.
+func filterType(typ reflect.Type) reflect.Type {
+	typ = baseType(typ)
+	if valueType, ok := nullableValueTypes[typ]; ok {
+		return valueType
+	}
+
+	return typ
+}
+
+func baseType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
+
+// filterCondition renders f as a condition on column for dbType using param as the parameter name.
+func filterCondition(dbType DBType, column string, f Filter, param string) (sql string, value any, err error) {
+	switch dbType {
+	case SpannerDBType:
+	case PostgresDBType, SQLiteDBType:
+		column = fmt.Sprintf(`"%s"`, column)
+	default:
+		return "", nil, errors.Newf("unsupported dbType: %s", dbType)
+	}
+
+	switch f.Operator {
+	case EqualOperator:
+		return fmt.Sprintf("%s = @%s", column, param), f.Value, nil
+	case NotEqualOperator:
+		return fmt.Sprintf("%s != @%s", column, param), f.Value, nil
+	case LessThanOperator:
+		return fmt.Sprintf("%s < @%s", column, param), f.Value, nil
+	case LessThanOrEqualOperator:
+		return fmt.Sprintf("%s <= @%s", column, param), f.Value, nil
+	case GreaterThanOperator:
+		return fmt.Sprintf("%s > @%s", column, param), f.Value, nil
+	case GreaterThanOrEqualOperator:
+		return fmt.Sprintf("%s >= @%s", column, param), f.Value, nil
+	case InOperator:
+		switch dbType {
+		case PostgresDBType:
+			return fmt.Sprintf("%s = ANY(@%s)", column, param), f.Value, nil
+		case SQLiteDBType:
+			values, err := json.Marshal(f.Value)
+			if err != nil {
+				return "", nil, errors.Wrap(err, "json.Marshal()")
+			}
+
+			return fmt.Sprintf("%s IN (SELECT value FROM json_each(@%s))", column, param), string(values), nil
+		}
+
+		return fmt.Sprintf("%s IN UNNEST(@%s)", column, param), f.Value, nil
+	case IsNullOperator:
+		if isNull, _ := f.Value.(bool); isNull {
+			return fmt.Sprintf("%s IS NULL", column), nil, nil
+		}
+
+		return fmt.Sprintf("%s IS NOT NULL", column), nil, nil
+	case PrefixOperator:
+		switch dbType {
+		case PostgresDBType:
+			return fmt.Sprintf("%s LIKE @%s", column, param), escapeLike(fmt.Sprint(f.Value)) + "%", nil
+		case SQLiteDBType:
+			// LIKE is case-insensitive in SQLite, so compare the leading characters instead
+			return fmt.Sprintf("substr(%s, 1, length(@%s)) = @%s", column, param, param), f.Value, nil
+		}
+
+		return fmt.Sprintf("STARTS_WITH(%s, @%s)", column, param), f.Value, nil
+	default:
+		return "", nil, errors.Newf("unknown filter operator %q", f.Operator)
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
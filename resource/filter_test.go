@@ -0,0 +1,230 @@
+package resource
+
+import (
+	"math/big"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+type filterResource struct {
+	ID        string     `spanner:"Id"        postgres:"id"         sqlite:"id"         query:"primaryKey"`
+	Status    string     `spanner:"Status"    postgres:"status"     sqlite:"status"`
+	Count     int64      `spanner:"Count"     postgres:"count"      sqlite:"count"`
+	CreatedAt time.Time  `spanner:"CreatedAt" postgres:"created_at" sqlite:"created_at"`
+	DeletedAt *time.Time `spanner:"DeletedAt" postgres:"deleted_at" sqlite:"deleted_at"`
+	Hidden    string
+}
+
+func (filterResource) Resource() accesstypes.Resource {
+	return "FilterResources"
+}
+
+func (filterResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+type filterRequest struct {
+	ID        string     `json:"id"`
+	Status    string     `json:"status"`
+	Count     int64      `json:"count"`
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt"`
+	Hidden    string     `json:"hidden"`
+}
+
+func Test_parseFilterParam(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := NewFieldMapper(filterRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+	fieldMap := structTags(reflect.TypeFor[filterResource](), string(SpannerDBType))
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []Filter
+		wantErr bool
+	}{
+		{
+			name:  "no filters",
+			query: url.Values{},
+		},
+		{
+			name: "typed values",
+			query: url.Values{"filter": {
+				"status:eq:active",
+				"count:gte:5",
+				"createdAt:lt:2024-01-02T00:00:00Z",
+				"deletedAt:isnull:true",
+				"status:in:active,pending",
+				"id:prefix:abc:def",
+			}},
+			want: []Filter{
+				{Field: "Status", Operator: EqualOperator, Value: "active"},
+				{Field: "Count", Operator: GreaterThanOrEqualOperator, Value: int64(5)},
+				{Field: "CreatedAt", Operator: LessThanOperator, Value: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Field: "DeletedAt", Operator: IsNullOperator, Value: true},
+				{Field: "Status", Operator: InOperator, Value: []string{"active", "pending"}},
+				{Field: "ID", Operator: PrefixOperator, Value: "abc:def"},
+			},
+		},
+		{name: "missing operator", query: url.Values{"filter": {"status"}}, wantErr: true},
+		{name: "unknown field", query: url.Values{"filter": {"nope:eq:1"}}, wantErr: true},
+		{name: "field without column", query: url.Values{"filter": {"hidden:eq:1"}}, wantErr: true},
+		{name: "unknown operator", query: url.Values{"filter": {"status:like:a"}}, wantErr: true},
+		{name: "invalid integer", query: url.Values{"filter": {"count:eq:five"}}, wantErr: true},
+		{name: "prefix on integer", query: url.Values{"filter": {"count:prefix:1"}}, wantErr: true},
+		{name: "invalid isnull", query: url.Values{"filter": {"deletedAt:isnull:maybe"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseFilterParam(mapper, reflect.TypeFor[filterResource](), fieldMap, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilterParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseFilterParam() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseFilterParam_nullable(t *testing.T) {
+	t.Parallel()
+
+	type nullableResource struct {
+		Name   spanner.NullString  `spanner:"Name"`
+		Count  spanner.NullInt64   `spanner:"Count"`
+		Due    spanner.NullDate    `spanner:"Due"`
+		Amount spanner.NullNumeric `spanner:"Amount"`
+	}
+	type nullableRequest struct {
+		Name   spanner.NullString  `json:"name"`
+		Count  spanner.NullInt64   `json:"count"`
+		Due    spanner.NullDate    `json:"due"`
+		Amount spanner.NullNumeric `json:"amount"`
+	}
+
+	mapper, err := NewFieldMapper(nullableRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+	fieldMap := structTags(reflect.TypeFor[nullableResource](), string(SpannerDBType))
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []Filter
+		wantErr bool
+	}{
+		{
+			name: "value types",
+			query: url.Values{"filter": {
+				"name:prefix:a",
+				"count:in:1,2",
+				"due:gte:2024-01-02",
+				"amount:lt:1.5",
+				"name:isnull:true",
+			}},
+			want: []Filter{
+				{Field: "Name", Operator: PrefixOperator, Value: "a"},
+				{Field: "Count", Operator: InOperator, Value: []int64{1, 2}},
+				{Field: "Due", Operator: GreaterThanOrEqualOperator, Value: civil.Date{Year: 2024, Month: 1, Day: 2}},
+				{Field: "Amount", Operator: LessThanOperator, Value: *big.NewRat(3, 2)},
+				{Field: "Name", Operator: IsNullOperator, Value: true},
+			},
+		},
+		{name: "invalid integer", query: url.Values{"filter": {"count:eq:one"}}, wantErr: true},
+		{name: "prefix on integer", query: url.Values{"filter": {"count:prefix:1"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseFilterParam(mapper, reflect.TypeFor[nullableResource](), fieldMap, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilterParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b big.Rat) bool { return a.Cmp(&b) == 0 })); diff != "" {
+				t.Errorf("parseFilterParam() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_Where_filters(t *testing.T) {
+	t.Parallel()
+
+	filters := []Filter{
+		{Field: "Status", Operator: InOperator, Value: []string{"active", "pending"}},
+		{Field: "Count", Operator: NotEqualOperator, Value: int64(3)},
+		{Field: "DeletedAt", Operator: IsNullOperator, Value: false},
+		{Field: "ID", Operator: PrefixOperator, Value: "a_b%"},
+	}
+
+	tests := []struct {
+		name   string
+		dbType DBType
+		want   Statement
+	}{
+		{
+			name:   "spanner",
+			dbType: SpannerDBType,
+			want: Statement{
+				Sql: "WHERE Id = @id AND Status IN UNNEST(@filter0) AND Count != @filter1 AND DeletedAt IS NOT NULL AND STARTS_WITH(Id, @filter3)",
+				Params: map[string]any{
+					"id":      "1",
+					"filter0": []string{"active", "pending"},
+					"filter1": int64(3),
+					"filter3": "a_b%",
+				},
+			},
+		},
+		{
+			name:   "postgres",
+			dbType: PostgresDBType,
+			want: Statement{
+				Sql: `WHERE "id" = @id AND "status" = ANY(@filter0) AND "count" != @filter1 AND "deleted_at" IS NOT NULL AND "id" LIKE @filter3`,
+				Params: map[string]any{
+					"id":      "1",
+					"filter0": []string{"active", "pending"},
+					"filter1": int64(3),
+					"filter3": `a\_b\%%`,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := NewQuerySet(&ResourceMetadata[filterResource]{
+				fieldMap: structTags(reflect.TypeFor[filterResource](), string(tt.dbType)),
+				dbType:   tt.dbType,
+			})
+			q.SetKey("ID", "1")
+			for _, f := range filters {
+				q.AddFilter(f)
+			}
+
+			got, err := q.Where()
+			if err != nil {
+				t.Fatalf("QuerySet.Where() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.Where() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
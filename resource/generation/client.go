@@ -3,7 +3,9 @@ package generation
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/format"
@@ -198,6 +200,7 @@ func (c *Client) createTableLookup(ctx context.Context) (map[string]*TableMetada
 		(t.TABLE_NAME IS NULL AND v.TABLE_NAME IS NOT NULL) as IS_VIEW,
 		ic.INDEX_NAME IS NOT NULL AS IS_INDEX,
 		MAX(COALESCE(i.IS_UNIQUE, false)) AS IS_UNIQUE_INDEX,
+		MIN(IF(ic.ORDINAL_POSITION = 1 AND i.INDEX_TYPE = 'INDEX', ic.INDEX_NAME, NULL)) AS INDEX_NAME,
 		c.GENERATION_EXPRESSION,
 		c.ORDINAL_POSITION,
 		COALESCE(d.KEY_ORDINAL_POSITION, 1) AS KEY_ORDINAL_POSITION,
@@ -289,6 +292,10 @@ func (c *Client) createLookupMapForQuery(ctx context.Context, qry string) (map[s
 			column.IsUniqueIndex = true
 		}
 
+		if r.IndexName != nil && column.IndexName == "" {
+			column.IndexName = *r.IndexName
+		}
+
 		table.Columns[r.ColumnName] = column
 		m[r.TableName] = table
 	}
@@ -367,6 +374,17 @@ func (c *Client) templateFuncs() map[string]any {
 
 			return false
 		},
+		"PrimaryKeys": func(fields []*FieldInfo) []*FieldInfo {
+			var keys []*FieldInfo
+			for _, f := range fields {
+				if f.IsPrimaryKey {
+					keys = append(keys, f)
+				}
+			}
+			slices.SortStableFunc(keys, func(a, b *FieldInfo) int { return cmp.Compare(a.KeyOrdinalPosition, b.KeyOrdinalPosition) })
+
+			return keys
+		},
 		"FormatPerm": func(s string) string {
 			if s == "" {
 				return ""
@@ -390,6 +408,14 @@ func (c *Client) templateFuncs() map[string]any {
 
 			return ""
 		},
+		"TSString": func(s string) (string, error) {
+			b, err := json.Marshal(s)
+			if err != nil {
+				return "", errors.Wrap(err, "json.Marshal()")
+			}
+
+			return string(b), nil
+		},
 		"FormatResourceInterfaceTypes": formatResourceInterfaceTypes,
 		"FormatTokenTag":               c.formatTokenTags,
 		"ResourceSearchType": func(searchType string) string {
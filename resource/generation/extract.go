@@ -152,6 +152,7 @@ func (c *Client) extractResourceTypes(pkg *types.Package) ([]*ResourceInfo, erro
 				IsForeignKey:       spannerColumn.IsForeignKey,
 				IsIndex:            spannerColumn.IsIndex,
 				IsUniqueIndex:      spannerColumn.IsUniqueIndex,
+				IndexName:          spannerColumn.IndexName,
 				OrdinalPosition:    spannerColumn.OrdinalPosition,
 				KeyOrdinalPosition: spannerColumn.KeyOrdinalPosition,
 				IsEnumerated:       isEnumerated,
@@ -25,6 +25,7 @@ package resources
 import (
 	"time"
 
+	"cloud.google.com/go/spanner"
 	"github.com/cccteam/ccc"
 	"github.com/cccteam/ccc/accesstypes"
 	"github.com/cccteam/ccc/resource"
@@ -43,6 +44,10 @@ func ({{ .Resource.Name }}) DefaultConfig() resource.Config {
 	return defaultConfig()
 }
 
+func ({{ .Resource.Name }}) PrimaryKeys() []accesstypes.Field {
+	return []accesstypes.Field{ {{- range $i, $field := PrimaryKeys .Resource.Fields }}{{ if $i }}, {{ end }}"{{ $field.Name }}"{{ end -}} }
+}
+
 type {{ .Resource.Name }}Query struct {
 	qSet *resource.QuerySet[{{ .Resource.Name }}]
 }
@@ -87,6 +92,11 @@ func (q *{{ .Resource.Name }}Query) Query() *resource.QuerySet[{{ .Resource.Name
 	return q.qSet
 }
 
+// SpannerReadTransaction returns a read-only transaction on client with the timestamp bound requested for the query.
+func (q *{{ .Resource.Name }}Query) SpannerReadTransaction(client *spanner.Client) *spanner.ReadOnlyTransaction {
+	return resource.SpannerReadTransaction(client, q.qSet)
+}
+
 func (q *{{ .Resource.Name }}Query) AddAllColumns() *{{ .Resource.Name }}Query {
 	{{- range $field := .Resource.Fields }}
 	q.qSet.AddField("{{ $field.Name }}")
@@ -266,7 +276,7 @@ package app
 	listTemplate = `func (a *App) {{ Pluralize .Resource.Name }}() http.HandlerFunc {
 	type {{ GoCamel .Resource.Name }} struct {
 		{{- range $field := .Resource.Fields }}
-		{{ $field.Name }} {{ $field.GoType}} ` + "`{{ $field.JSONTag }} {{ $field.IndexTag}} {{ $field.ListPermTag }} {{ $field.QueryTag }} {{FormatTokenTag (Pluralize $field.Parent.Name) $field.SpannerName}}`" + `
+		{{ $field.Name }} {{ $field.GoType}} ` + "`{{ $field.JSONTag }} {{ $field.IndexTag}} {{ $field.ListPermTag }} {{ $field.ReferencesTag }} {{ $field.QueryTag }} {{FormatTokenTag (Pluralize $field.Parent.Name) $field.SpannerName}}`" + `
 		{{- end }}
 	}
 
@@ -300,7 +310,7 @@ package app
 	readTemplate = `func (a *App) {{ .Resource.Name }}() http.HandlerFunc {
 	type response struct {
 		{{- range $field := .Resource.Fields }}
-		{{ $field.Name }} {{ $field.GoType}} ` + "`{{ $field.JSONTag }} {{ $field.UniqueIndexTag }} {{ $field.ReadPermTag }} {{ $field.QueryTag }} {{ FormatTokenTag (Pluralize $field.Parent.Name) $field.SpannerName }}`" + `
+		{{ $field.Name }} {{ $field.GoType}} ` + "`{{ $field.JSONTag }} {{ $field.UniqueIndexTag }} {{ $field.ReadPermTag }} {{ $field.ReferencesTag }} {{ $field.QueryTag }} {{ FormatTokenTag (Pluralize $field.Parent.Name) $field.SpannerName }}`" + `
 		{{- end }}
 	}
 
@@ -415,6 +425,7 @@ package app
 	typescriptPermissionTemplate = `// Code generated by resourcegeneration. DO NOT EDIT.
 import { Domain, Permission, Resource } from '@cccteam/ccc-lib';
 {{- $permissions := .Permissions }}
+{{- $permissionKinds := .PermissionKinds }}
 {{- $resources := .Resources }}
 {{- $resourcetags := .ResourceTags }}
 {{- $resourcePerms := .ResourcePermissions }}
@@ -426,6 +437,14 @@ export const Permissions = {
 {{- end}}
 };
 
+export type PermissionKind = 'viewing' | 'mutating' | 'action';
+
+export const PermissionKinds: Record<Permission, PermissionKind> = {
+{{- range $perm := $permissions }}
+  [Permissions.{{ $perm }}]: '{{ index $permissionKinds $perm }}',
+{{- end}}
+};
+
 export const Domains = {
 {{- range $domain := $domains }}
   {{ $domain }}: '{{ $domain }}' as Domain,
@@ -444,6 +463,18 @@ export const {{ $resource }} = {
 {{- end }}
 };
 {{ end }}
+export const PermissionDescriptions: Partial<Record<Permission, string>> = {
+{{- range $perm, $description := .PermissionDescriptions }}
+  [Permissions.{{ $perm }}]: {{ TSString $description }},
+{{- end }}
+};
+
+export const ResourceDescriptions: Partial<Record<Resource, string>> = {
+{{- range $resource, $description := .ResourceDescriptions }}
+  ['{{ $resource }}' as Resource]: {{ TSString $description }},
+{{- end }}
+};
+
 type PermissionResources = Record<Permission, boolean>;
 type PermissionMappings = Record<Resource, PermissionResources>;
 
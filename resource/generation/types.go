@@ -92,6 +92,7 @@ type InformationSchemaResult struct {
 	IsView               bool    `spanner:"IS_VIEW"`
 	IsIndex              bool    `spanner:"IS_INDEX"`
 	IsUniqueIndex        bool    `spanner:"IS_UNIQUE_INDEX"`
+	IndexName            *string `spanner:"INDEX_NAME"`
 	GenerationExpression *string `spanner:"GENERATION_EXPRESSION"`
 	OrdinalPosition      int64   `spanner:"ORDINAL_POSITION"`
 	KeyOrdinalPosition   int64   `spanner:"KEY_ORDINAL_POSITION"`
@@ -113,6 +114,7 @@ type ColumnMeta struct {
 	IsNullable         bool
 	IsIndex            bool
 	IsUniqueIndex      bool
+	IndexName          string // Secondary index with the column as its first key column
 	OrdinalPosition    int64
 	KeyOrdinalPosition int64
 	ReferencedTable    string
@@ -176,6 +178,7 @@ type FieldInfo struct {
 	IsForeignKey       bool
 	IsIndex            bool
 	IsUniqueIndex      bool
+	IndexName          string
 	OrdinalPosition    int64 // Position of column in the table definition
 	KeyOrdinalPosition int64 // Position of primary or foreign key in a compound key definition
 	IsEnumerated       bool
@@ -222,6 +225,10 @@ func (f *FieldInfo) JSONTagForPatch() string {
 }
 
 func (f *FieldInfo) IndexTag() string {
+	if f.IsIndex && f.IndexName != "" {
+		return fmt.Sprintf(`index:"true" forceIndex:%q`, f.IndexName)
+	}
+
 	if f.IsIndex {
 		return `index:"true"`
 	}
@@ -237,6 +244,15 @@ func (f *FieldInfo) UniqueIndexTag() string {
 	return ""
 }
 
+// ReferencesTag declares the resource referenced by a foreign key so that it can be expanded in list and read queries.
+func (f *FieldInfo) ReferencesTag() string {
+	if f.IsForeignKey && f.ReferencedResource != "" && f.ReferencedField != "" {
+		return fmt.Sprintf("references:%q", f.ReferencedResource+"."+f.ReferencedField)
+	}
+
+	return ""
+}
+
 func (f *FieldInfo) IsImmutable() bool {
 	return slices.Contains(f.Conditions, "immutable")
 }
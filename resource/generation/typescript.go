@@ -20,11 +20,14 @@ func (c *Client) runTypescriptPermissionGeneration() error {
 	}
 
 	output, err := c.generateTemplateOutput(typescriptPermissionTemplate, map[string]any{
-		"Permissions":         templateData.Permissions,
-		"Resources":           templateData.Resources,
-		"ResourceTags":        templateData.ResourceTags,
-		"ResourcePermissions": templateData.ResourcePermissions,
-		"Domains":             templateData.Domains,
+		"Permissions":            templateData.Permissions,
+		"PermissionKinds":        templateData.PermissionKinds,
+		"Resources":              templateData.Resources,
+		"ResourceTags":           templateData.ResourceTags,
+		"ResourcePermissions":    templateData.ResourcePermissions,
+		"Domains":                templateData.Domains,
+		"PermissionDescriptions": templateData.PermissionDescriptions,
+		"ResourceDescriptions":   templateData.ResourceDescriptions,
 	})
 	if err != nil {
 		return errors.Wrap(err, "c.generateTemplateOutput()")
@@ -0,0 +1,484 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/cccteam/spxscan"
+	"github.com/go-playground/errors/v5"
+)
+
+// Include reads a child collection along with the parent rows, for example the OrderLines of each Order.
+// The children are read with one query for all parent rows and set on the field of the parent destination
+// struct tagged with the include name, which must be a slice of structs or struct pointers:
+//
+//	Lines []*OrderLine `json:"lines" include:"lines" spanner:"-"`
+type Include struct {
+	// Name matches the include struct tag of the parent destination field.
+	Name string
+	// Query is the query set of the child resource, which sets the child's fields, filters and sort.
+	Query IncludeQuerier
+	// ForeignKeys are the child fields holding the primary key of the parent, in primary key order.
+	// When empty the child is assumed to be interleaved in the parent, so that its primary key starts
+	// with the parent's primary key.
+	ForeignKeys []accesstypes.Field
+	// Limit is the maximum number of children read for each parent, or 0 for all of them.
+	Limit int
+}
+
+// IncludeQuerier is the query set of a child resource read with Include. It is implemented by *QuerySet.
+type IncludeQuerier interface {
+	Resource() accesstypes.Resource
+	includeForeignKeys(n int) ([]accesstypes.Field, error)
+	includeStmt(dbType DBType, foreignKeys []accesstypes.Field, parentKeys []KeySet, limit int) (Statement, error)
+}
+
+// Include adds a child collection to read with the parent rows by SpannerRead, SpannerList, PostgresRead,
+// PostgresList, SQLiteRead and SQLiteList. The primary key of Resource is also selected, as it is
+// needed to match the children to their parent, so the caller must have permission to view it.
+// QueryDecoder.Decode returns a forbidden error for an include without that permission.
+func (q *QuerySet[Resource]) Include(include Include) *QuerySet[Resource] {
+	for _, key := range q.rMeta.primaryKeys {
+		q.AddField(key)
+	}
+
+	for i := range q.includes {
+		if q.includes[i].Name == include.Name {
+			q.includes[i] = include
+
+			return q
+		}
+	}
+	q.includes = append(q.includes, include)
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Includes() []Include {
+	return q.includes
+}
+
+// spannerIncludes reads the includes of the parent rows in dst.
+func (q *QuerySet[Resource]) spannerIncludes(ctx context.Context, txn *spanner.ReadOnlyTransaction, dst any) error {
+	return q.loadIncludes(dst, func(s Statement, children any) error {
+		stmt := spanner.NewStatement(s.Sql)
+		maps.Insert(stmt.Params, maps.All(s.Params))
+
+		if err := spxscan.Select(ctx, txn, children, stmt); err != nil {
+			return errors.Wrap(err, "spxscan.Select()")
+		}
+
+		return nil
+	})
+}
+
+// postgresIncludes reads the includes of the parent rows in dst.
+func (q *QuerySet[Resource]) postgresIncludes(ctx context.Context, db PostgresQuerier, dst any) error {
+	return q.loadIncludes(dst, func(stmt Statement, children any) error {
+		rows, err := postgresQuery(ctx, db, stmt)
+		if err != nil {
+			return err
+		}
+
+		return postgresSelect(rows, children)
+	})
+}
+
+// sqliteIncludes reads the includes of the parent rows in dst.
+func (q *QuerySet[Resource]) sqliteIncludes(ctx context.Context, db SQLiteQuerier, dst any) error {
+	return q.loadIncludes(dst, func(stmt Statement, children any) error {
+		rows, err := sqliteQuery(ctx, db, stmt)
+		if err != nil {
+			return err
+		}
+
+		return sqliteSelect(rows, children)
+	})
+}
+
+// loadIncludes reads each include for the parent rows in dst, a pointer to a struct or to a slice of structs
+// or struct pointers, running the child statements with selectRows, and sets the children on their parent.
+// Parents without children get an empty slice.
+func (q *QuerySet[Resource]) loadIncludes(dst any, selectRows func(stmt Statement, children any) error) error {
+	if len(q.includes) == 0 {
+		return nil
+	}
+
+	parents, err := includeParents(dst)
+	if err != nil {
+		return err
+	}
+	if len(parents) == 0 {
+		return nil
+	}
+
+	parentKeys := make([]KeySet, 0, len(parents))
+	byRowID := make(map[string][]reflect.Value, len(parents))
+	for _, parent := range parents {
+		keySet, err := rowKeySet(parent, q.rMeta.primaryKeys)
+		if err != nil {
+			return err
+		}
+		if _, ok := byRowID[keySet.RowID()]; !ok {
+			parentKeys = append(parentKeys, keySet)
+		}
+		byRowID[keySet.RowID()] = append(byRowID[keySet.RowID()], parent)
+	}
+
+	for _, include := range q.includes {
+		field, err := includeField(parents[0].Type(), include.Name)
+		if err != nil {
+			return err
+		}
+
+		foreignKeys := include.ForeignKeys
+		if len(foreignKeys) == 0 {
+			if foreignKeys, err = include.Query.includeForeignKeys(len(q.rMeta.primaryKeys)); err != nil {
+				return err
+			}
+		}
+		if len(foreignKeys) != len(q.rMeta.primaryKeys) {
+			return errors.Newf("include %s has %d foreign keys, %s has %d primary key fields", include.Name, len(foreignKeys), q.Resource(), len(q.rMeta.primaryKeys))
+		}
+
+		childKeys := make([]KeySet, 0, len(parentKeys))
+		for _, keySet := range parentKeys {
+			var childKey KeySet
+			for i, part := range keySet.Parts() {
+				childKey = childKey.Add(foreignKeys[i], part.Value)
+			}
+			childKeys = append(childKeys, childKey)
+		}
+
+		stmt, err := include.Query.includeStmt(q.rMeta.dbType, foreignKeys, childKeys, include.Limit)
+		if err != nil {
+			return errors.Wrapf(err, "include %s", include.Name)
+		}
+
+		children := reflect.New(field.Type)
+		if err := selectRows(stmt, children.Interface()); err != nil {
+			return errors.Wrapf(err, "include %s", include.Name)
+		}
+
+		grouped := make(map[string]reflect.Value, len(parentKeys))
+		for i := range children.Elem().Len() {
+			child := children.Elem().Index(i)
+
+			keySet, err := rowKeySet(child, foreignKeys)
+			if err != nil {
+				return err
+			}
+			rows, ok := grouped[keySet.RowID()]
+			if !ok {
+				rows = reflect.MakeSlice(field.Type, 0, 1)
+			}
+			grouped[keySet.RowID()] = reflect.Append(rows, child)
+		}
+
+		for rowID, rows := range byRowID {
+			children, ok := grouped[rowID]
+			if !ok {
+				children = reflect.MakeSlice(field.Type, 0, 0)
+			}
+			for _, parent := range rows {
+				parent.FieldByIndex(field.Index).Set(children)
+			}
+		}
+	}
+
+	return nil
+}
+
+// includeForeignKeys returns the first n primary key fields, which hold the parent's primary key
+// when Resource is interleaved in the parent.
+func (q *QuerySet[Resource]) includeForeignKeys(n int) ([]accesstypes.Field, error) {
+	if len(q.rMeta.primaryKeys) <= n {
+		return nil, errors.Newf("%s is not interleaved, its primary key has %d fields, foreign keys are required", q.Resource(), len(q.rMeta.primaryKeys))
+	}
+
+	return q.rMeta.primaryKeys[:n], nil
+}
+
+// includeStmt builds the statement selecting the rows whose foreign keys match any of parentKeys, ordered
+// by the sort fields and the primary key. When limit is set at most limit rows are selected for each parent,
+// and as the sort fields may not be selected, the rows are ordered by their rank within the parent.
+func (q *QuerySet[Resource]) includeStmt(dbType DBType, foreignKeys []accesstypes.Field, parentKeys []KeySet, limit int) (Statement, error) {
+	if q.rMeta.dbType != dbType {
+		return Statement{}, errors.Newf("can not include %s with dbType %s in a query with dbType %s", q.Resource(), q.rMeta.dbType, dbType)
+	}
+	if len(q.expansions) > 0 || len(q.includes) > 0 {
+		return Statement{}, errors.Newf("expansions and includes are not supported on included resource %s", q.Resource())
+	}
+
+	include := *q
+	include.fields = slices.Clone(q.fields)
+	for _, key := range foreignKeys {
+		include.AddField(key)
+	}
+
+	columns, err := include.Columns()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.Columns()")
+	}
+
+	keys, err := include.keySetsWhere(parentKeys)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	where, err := include.matchWhere()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	whereSql := "WHERE " + keys.Sql
+	if where.Sql != "" {
+		whereSql = "WHERE (" + keys.Sql + ") AND " + strings.TrimPrefix(where.Sql, "WHERE ")
+	}
+	maps.Insert(keys.Params, maps.All(where.Params))
+
+	sort, err := q.searchOrder()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	order, err := orderBy(q.rMeta.dbType, q.rMeta.fieldMap, sort)
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "orderBy()")
+	}
+
+	table, err := include.table()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	if limit <= 0 {
+		return Statement{
+			Sql: fmt.Sprintf(`
+			SELECT
+				%s
+			FROM %s
+			%s
+			ORDER BY %s`, columns, table, whereSql, order,
+			),
+			Params: keys.Params,
+		}, nil
+	}
+
+	partition := make([]string, 0, len(foreignKeys))
+	for _, key := range foreignKeys {
+		c, ok := q.rMeta.fieldMap[key]
+		if !ok {
+			return Statement{}, errors.Newf("field %s not found in struct", key)
+		}
+
+		column, err := quoteIdent(q.rMeta.dbType, c.tag)
+		if err != nil {
+			return Statement{}, err
+		}
+		partition = append(partition, column)
+	}
+
+	if q.rMeta.dbType == SpannerDBType {
+		return include.spannerIncludeStmt(partition, keys, where, order, limit)
+	}
+
+	sql := fmt.Sprintf(`
+			SELECT
+				%s
+			FROM (
+				SELECT
+					%s, ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS includerank
+				FROM %s
+				%s
+			) AS included
+			WHERE includerank <= %d
+			ORDER BY %s, includerank`, columns, columns, strings.Join(partition, ", "), order, table, whereSql, limit, strings.Join(partition, ", "),
+	)
+
+	return Statement{
+		Sql:    sql,
+		Params: keys.Params,
+	}, nil
+}
+
+// spannerIncludeStmt returns the statement reading at most limit children per parent in Spanner. Each
+// parent's children are read by a correlated ARRAY subquery, as window functions are not supported
+// by Spanner GoogleSQL.
+func (q *QuerySet[Resource]) spannerIncludeStmt(foreignKeys []string, keys, where Statement, order string, limit int) (Statement, error) {
+	tags, err := q.columnTags()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	columns := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		columns = append(columns, "included."+tag)
+	}
+
+	correlated := make([]string, 0, len(foreignKeys))
+	parentKeys := make([]string, 0, len(foreignKeys))
+	for _, key := range foreignKeys {
+		correlated = append(correlated, fmt.Sprintf("%s = parent.%s", key, key))
+		parentKeys = append(parentKeys, "parent."+key)
+	}
+	childWhere := "WHERE " + strings.Join(correlated, " AND ")
+	if where.Sql != "" {
+		childWhere += " AND (" + strings.TrimPrefix(where.Sql, "WHERE ") + ")"
+	}
+
+	sql := fmt.Sprintf(`
+			SELECT
+				%s
+			FROM (
+				SELECT DISTINCT %s
+				FROM %s
+				WHERE %s
+			) AS parent,
+			UNNEST(ARRAY(
+				SELECT AS STRUCT
+					%s
+				FROM %s
+				%s
+				ORDER BY %s
+				LIMIT %d
+			)) AS included WITH OFFSET AS includerank
+			ORDER BY %s, includerank`,
+		strings.Join(columns, ", "), strings.Join(foreignKeys, ", "), q.Resource(), keys.Sql,
+		strings.Join(tags, ", "), q.Resource(), childWhere, order, limit, strings.Join(parentKeys, ", "),
+	)
+
+	return Statement{
+		Sql:    sql,
+		Params: keys.Params,
+	}, nil
+}
+
+// includeParents returns the addressable parent structs in dst, a pointer to a struct or to a slice of
+// structs or struct pointers.
+func includeParents(dst any) ([]reflect.Value, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, errors.Newf("dst must be a non-nil pointer, got %T", dst)
+	}
+
+	switch v.Elem().Kind() {
+	case reflect.Struct:
+		return []reflect.Value{v.Elem()}, nil
+	case reflect.Slice:
+		parents := make([]reflect.Value, 0, v.Elem().Len())
+		for i := range v.Elem().Len() {
+			parent := v.Elem().Index(i)
+			if parent.Kind() == reflect.Pointer {
+				if parent.IsNil() {
+					continue
+				}
+				parent = parent.Elem()
+			}
+			if parent.Kind() != reflect.Struct {
+				return nil, errors.Newf("dst must be a pointer to a slice of structs, got %T", dst)
+			}
+			parents = append(parents, parent)
+		}
+
+		return parents, nil
+	default:
+		return nil, errors.Newf("dst must be a pointer to a struct or a slice, got %T", dst)
+	}
+}
+
+// includeField returns the field of t tagged with the include name, which must be a slice of structs or struct pointers.
+func includeField(t reflect.Type, name string) (reflect.StructField, error) {
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Tag.Get("include") != name {
+			continue
+		}
+
+		elem := field.Type
+		if elem.Kind() == reflect.Slice {
+			elem = elem.Elem()
+			if elem.Kind() == reflect.Pointer {
+				elem = elem.Elem()
+			}
+		}
+		if field.Type.Kind() != reflect.Slice || elem.Kind() != reflect.Struct {
+			return reflect.StructField{}, errors.Newf("field %s.%s tagged include:%q must be a slice of structs, got %s", t, field.Name, name, field.Type)
+		}
+
+		return field, nil
+	}
+
+	return reflect.StructField{}, errors.Newf("no field tagged include:%q in %s", name, t)
+}
+
+// rowKeySet returns the values of the fields of row, a struct or struct pointer, as a KeySet.
+func rowKeySet(row reflect.Value, fields []accesstypes.Field) (KeySet, error) {
+	if row.Kind() == reflect.Pointer {
+		row = row.Elem()
+	}
+
+	var keySet KeySet
+	for _, key := range fields {
+		field := row.FieldByName(string(key))
+		if !field.IsValid() {
+			return KeySet{}, errors.Newf("field %s not found in %s", key, row.Type())
+		}
+		for field.Kind() == reflect.Pointer && !field.IsNil() {
+			field = field.Elem()
+		}
+		keySet = keySet.Add(key, field.Interface())
+	}
+
+	return keySet, nil
+}
+
+// includeParam is a child collection requested with the include query parameter.
+type includeParam struct {
+	name  string
+	limit int
+}
+
+// parseIncludeParam parses the include query parameter, a comma separated list of include names, each
+// optionally followed by the maximum number of children to read for each parent. For example:
+//
+//	?include=lines:10,notes
+func parseIncludeParam(names []string, queryParams url.Values) ([]includeParam, error) {
+	param := queryParams.Get("include")
+	if param == "" {
+		return nil, nil
+	}
+
+	var params []includeParam
+	for _, include := range strings.Split(param, ",") {
+		name, limitVal, hasLimit := strings.Cut(include, ":")
+		if !slices.Contains(names, name) {
+			return nil, httpio.NewBadRequestMessagef("unknown include: %s", name)
+		}
+
+		if slices.ContainsFunc(params, func(p includeParam) bool { return p.name == name }) {
+			return nil, httpio.NewBadRequestMessagef("include %s is repeated", name)
+		}
+
+		var limit int
+		if hasLimit {
+			var err error
+			limit, err = strconv.Atoi(limitVal)
+			if err != nil || limit <= 0 {
+				return nil, httpio.NewBadRequestMessagef("invalid limit for include %s: %s", name, limitVal)
+			}
+		}
+
+		params = append(params, includeParam{name: name, limit: limit})
+	}
+
+	return params, nil
+}
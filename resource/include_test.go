@@ -0,0 +1,297 @@
+package resource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/ccc/resource/mock/mock_accesstypes"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+type lineResource struct {
+	FilterID string `spanner:"FilterId" sqlite:"filter_id" query:"primaryKey"`
+	ID       string `spanner:"Id"       sqlite:"id"        query:"primaryKey"`
+	Position int64  `spanner:"Position" sqlite:"position"`
+}
+
+func (lineResource) Resource() accesstypes.Resource {
+	return "FilterLines"
+}
+
+func (lineResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+type filterWithLines struct {
+	filterResource
+	Lines []*lineResource `include:"lines" spanner:"-"`
+}
+
+func newLineQuerySet(dbType DBType) *QuerySet[lineResource] {
+	return NewQuerySet(&ResourceMetadata[lineResource]{
+		fieldMap:    structTags(reflect.TypeFor[lineResource](), string(dbType)),
+		primaryKeys: primaryKeyFields(reflect.TypeFor[lineResource]()),
+		dbType:      dbType,
+	})
+}
+
+func newSQLiteLinesDB(t *testing.T) SQLiteQuerier {
+	t.Helper()
+
+	db := newSQLiteDB(t)
+	if _, err := db.Exec(`
+		CREATE TABLE FilterLines ("filter_id" TEXT, "id" TEXT, "position" INTEGER, PRIMARY KEY ("filter_id", "id"));
+		INSERT INTO FilterLines VALUES ('a', '1', 1), ('a', '2', 2), ('a', '3', 3), ('b', '1', 1);
+	`); err != nil {
+		t.Fatalf("sql.DB.Exec() error = %v", err)
+	}
+
+	return db
+}
+
+func TestQuerySet_SQLiteList_include(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		include Include
+		want    map[string][]*lineResource
+	}{
+		{
+			name: "interleaved",
+			include: Include{
+				Name:  "lines",
+				Query: newLineQuerySet(SQLiteDBType).AddField("ID").AddField("Position"),
+			},
+			want: map[string][]*lineResource{
+				"a": {{FilterID: "a", ID: "1", Position: 1}, {FilterID: "a", ID: "2", Position: 2}, {FilterID: "a", ID: "3", Position: 3}},
+				"b": {{FilterID: "b", ID: "1", Position: 1}},
+				"c": {},
+			},
+		},
+		{
+			name: "foreign key with limit and sort",
+			include: Include{
+				Name:        "lines",
+				Query:       newLineQuerySet(SQLiteDBType).AddField("ID").OrderBy(SortField{Field: "Position", Direction: Descending}),
+				ForeignKeys: []accesstypes.Field{"FilterID"},
+				Limit:       2,
+			},
+			want: map[string][]*lineResource{
+				"a": {{FilterID: "a", ID: "3"}, {FilterID: "a", ID: "2"}},
+				"b": {{FilterID: "b", ID: "1"}},
+				"c": {},
+			},
+		},
+		{
+			name: "filtered children",
+			include: Include{
+				Name:  "lines",
+				Query: newLineQuerySet(SQLiteDBType).AddField("ID").AddFilter(Filter{Field: "Position", Operator: GreaterThanOperator, Value: 1}),
+			},
+			want: map[string][]*lineResource{
+				"a": {{FilterID: "a", ID: "2"}, {FilterID: "a", ID: "3"}},
+				"b": {},
+				"c": {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := newSQLiteLinesDB(t)
+			q := newFilterQuerySet(SQLiteDBType).AddField("Status").Include(tt.include)
+
+			var got []*filterWithLines
+			if err := q.SQLiteList(context.Background(), db, &got); err != nil {
+				t.Fatalf("QuerySet.SQLiteList() error = %v", err)
+			}
+
+			lines := make(map[string][]*lineResource, len(got))
+			for _, parent := range got {
+				lines[parent.ID] = parent.Lines
+			}
+			if diff := cmp.Diff(tt.want, lines); diff != "" {
+				t.Errorf("QuerySet.SQLiteList() lines mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_SQLiteRead_include(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLiteLinesDB(t)
+	q := newFilterQuerySet(SQLiteDBType).AddField("Status").
+		Include(Include{Name: "lines", Query: newLineQuerySet(SQLiteDBType).AddField("ID"), Limit: 1})
+	q.SetKey("ID", "a")
+
+	var got filterWithLines
+	if err := q.SQLiteRead(context.Background(), db, &got); err != nil {
+		t.Fatalf("QuerySet.SQLiteRead() error = %v", err)
+	}
+
+	want := filterWithLines{
+		filterResource: filterResource{ID: "a", Status: "active"},
+		Lines:          []*lineResource{{FilterID: "a", ID: "1"}},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(filterWithLines{})); diff != "" {
+		t.Errorf("QuerySet.SQLiteRead() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQuerySet_includeStmt(t *testing.T) {
+	t.Parallel()
+
+	keys := []KeySet{KeySet{}.Add("FilterID", "a"), KeySet{}.Add("FilterID", "b")}
+
+	t.Run("spanner limit", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := newLineQuerySet(SpannerDBType).AddField("Position").includeStmt(SpannerDBType, []accesstypes.Field{"FilterID"}, keys, 5)
+		if err != nil {
+			t.Fatalf("QuerySet.includeStmt() error = %v", err)
+		}
+
+		want := `SELECT included.FilterId, included.Position FROM ( SELECT DISTINCT FilterId ` +
+			`FROM FilterLines WHERE FilterId IN UNNEST(@batchkeys) ) AS parent, ` +
+			`UNNEST(ARRAY( SELECT AS STRUCT FilterId, Position FROM FilterLines WHERE FilterId = parent.FilterId ` +
+			`ORDER BY FilterId ASC, Id ASC LIMIT 5 )) AS included WITH OFFSET AS includerank ` +
+			`ORDER BY parent.FilterId, includerank`
+		if diff := cmp.Diff(want, strings.Join(strings.Fields(got.Sql), " ")); diff != "" {
+			t.Errorf("QuerySet.includeStmt() SQL mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(map[string]any{"batchkeys": []string{"a", "b"}}, got.Params); diff != "" {
+			t.Errorf("QuerySet.includeStmt() params mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("mismatched dbType", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := newLineQuerySet(PostgresDBType).includeStmt(SpannerDBType, []accesstypes.Field{"FilterID"}, keys, 0); err == nil {
+			t.Errorf("QuerySet.includeStmt() error = nil, want error")
+		}
+	})
+
+	t.Run("not interleaved", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := newFilterQuerySet(SpannerDBType).includeForeignKeys(1); err == nil {
+			t.Errorf("QuerySet.includeForeignKeys() error = nil, want error")
+		}
+	})
+}
+
+func Test_parseIncludeParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []includeParam
+		wantErr bool
+	}{
+		{name: "no include", query: url.Values{}},
+		{
+			name:  "includes with limit",
+			query: url.Values{"include": {"lines:10,notes"}},
+			want:  []includeParam{{name: "lines", limit: 10}, {name: "notes"}},
+		},
+		{name: "unknown include", query: url.Values{"include": {"nope"}}, wantErr: true},
+		{name: "invalid limit", query: url.Values{"include": {"lines:0"}}, wantErr: true},
+		{name: "repeated include", query: url.Values{"include": {"lines,lines:2"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseIncludeParam([]string{"lines", "notes"}, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIncludeParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(includeParam{})); diff != "" {
+				t.Errorf("parseIncludeParam() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type lineRequest struct {
+	FilterID string `json:"filterId"`
+	ID       string `json:"id"`
+	Position int64  `json:"position"`
+}
+
+type restrictedFilterRequest struct {
+	ID     string `json:"id"     perm:"Read"`
+	Status string `json:"status"`
+}
+
+func TestQueryDecoder_Decode_include(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		hasPerm bool
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "primary key permitted",
+			hasPerm: true,
+			want:    []string{"lines"},
+		},
+		{
+			name:    "primary key restricted",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			enforcer := mock_accesstypes.NewMockEnforcer(ctrl)
+			enforcer.EXPECT().RequireResources(gomock.Any(), gomock.Any(), gomock.Any(), accesstypes.Read, accesstypes.Resource("FilterResources")).Return(true, nil, nil).AnyTimes()
+			enforcer.EXPECT().RequireResources(gomock.Any(), gomock.Any(), gomock.Any(), accesstypes.Read, accesstypes.Resource("FilterResources.id")).Return(tt.hasPerm, nil, nil).AnyTimes()
+			enforcer.EXPECT().RequireResources(gomock.Any(), gomock.Any(), gomock.Any(), accesstypes.Read, accesstypes.Resource("FilterLines")).Return(true, nil, nil).AnyTimes()
+
+			domain := func(context.Context) accesstypes.Domain { return "" }
+			user := func(context.Context) accesstypes.User { return "" }
+			lines, err := NewQueryDecoder(ccc.Must(NewResourceSet[lineResource, lineRequest](accesstypes.Read)), enforcer, domain, user)
+			if err != nil {
+				t.Fatalf("NewQueryDecoder() error = %v", err)
+			}
+			d, err := NewQueryDecoder(ccc.Must(NewResourceSet[filterResource, restrictedFilterRequest](accesstypes.Read)), enforcer, domain, user)
+			if err != nil {
+				t.Fatalf("NewQueryDecoder() error = %v", err)
+			}
+			d.AddInclude("lines", lines, "FilterID")
+
+			got, err := d.Decode(httptest.NewRequest(http.MethodGet, "/?include=lines&columns=status", http.NoBody))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryDecoder.Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			names := make([]string, 0, len(got.Includes()))
+			for _, include := range got.Includes() {
+				names = append(names, include.Name)
+			}
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("QueryDecoder.Decode() includes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+)
+
+// SpannerIndex is a secondary index of a Spanner resource.
+type SpannerIndex struct {
+	Name string
+	// Fields are the key fields of the index.
+	Fields []accesstypes.Field
+	// Storing are the fields stored in the index. Along with Fields and the primary key, they are the fields
+	// which can be read from the index alone.
+	Storing []accesstypes.Field
+}
+
+// UseIndex reads with index. Lookups by the key fields of the index which only select fields in the index use
+// the Read API, and other queries force the index with a FORCE_INDEX hint. Search queries, which use
+// their search index, are not hinted, and Postgres and SQLite choose their indexes themselves.
+func (q *QuerySet[Resource]) UseIndex(index SpannerIndex) *QuerySet[Resource] {
+	q.index = index
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Index() SpannerIndex {
+	return q.index
+}
+
+// spannerTable returns the table to select from, with the FORCE_INDEX hint of the index set with UseIndex.
+func (q *QuerySet[Resource]) spannerTable() string {
+	if q.index.Name == "" {
+		return string(q.Resource())
+	}
+
+	return string(q.Resource()) + "@{FORCE_INDEX=" + q.index.Name + "}"
+}
+
+// spannerReadKey returns the key and the index, if any, to read the rows with the Read API. The Read API is used for
+// lookups by the whole primary key, or by the key fields of the index set with UseIndex when all of the selected fields
+// are in the index, which are not otherwise filtered, searched, sorted or paginated.
+func (q *QuerySet[Resource]) spannerReadKey() (key spanner.Key, index string, ok bool) {
+	keyFields := q.KeySet().keys()
+	if len(keyFields) == 0 || len(q.filters) > 0 || len(q.searches) > 0 || len(q.expansions) > 0 || len(q.sort) > 0 ||
+		q.limit > 0 || q.offset > 0 || q.cursor != "" || q.total {
+		return nil, "", false
+	}
+
+	if sameFields(keyFields, q.rMeta.primaryKeys) {
+		return q.spannerKey(q.rMeta.primaryKeys), "", true
+	}
+
+	if q.index.Name == "" || !sameFields(keyFields, q.index.Fields) {
+		return nil, "", false
+	}
+
+	for _, field := range q.fields {
+		if !slices.Contains(q.index.Fields, field) && !slices.Contains(q.index.Storing, field) && !slices.Contains(q.rMeta.primaryKeys, field) {
+			return nil, "", false
+		}
+	}
+
+	return q.spannerKey(q.index.Fields), q.index.Name, true
+}
+
+// spannerKey returns the key values for fields, in their order.
+func (q *QuerySet[Resource]) spannerKey(fields []accesstypes.Field) spanner.Key {
+	key := make(spanner.Key, 0, len(fields))
+	for _, field := range fields {
+		key = append(key, q.keys.Get(field))
+	}
+
+	return key
+}
+
+// spannerReadRow reads the row for the key into dst, a pointer to a struct, with ReadRow or ReadUsingIndex.
+func (q *QuerySet[Resource]) spannerReadRow(ctx context.Context, txn *spanner.ReadOnlyTransaction, key spanner.Key, index string, dst any) error {
+	columns, err := q.columnTags()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.columnTags()")
+	}
+
+	var row *spanner.Row
+	if index == "" {
+		row, err = txn.ReadRow(ctx, string(q.Resource()), key, columns)
+		if err != nil {
+			if spanner.ErrCode(err) == codes.NotFound {
+				return httpio.NewNotFoundMessagef("%s (%s) not found", q.Resource(), q.KeySet().String())
+			}
+
+			return errors.Wrap(err, "spanner.ReadOnlyTransaction.ReadRow()")
+		}
+	} else {
+		iter := txn.ReadUsingIndex(ctx, string(q.Resource()), index, key, columns)
+		defer iter.Stop()
+
+		row, err = iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return httpio.NewNotFoundMessagef("%s (%s) not found", q.Resource(), q.KeySet().String())
+			}
+
+			return errors.Wrap(err, "spanner.RowIterator.Next()")
+		}
+	}
+
+	if err := row.ToStruct(dst); err != nil {
+		return errors.Wrap(err, "spanner.Row.ToStruct()")
+	}
+
+	return nil
+}
+
+// spannerReadRows reads the rows for the key into dst, a pointer to a slice of structs or struct pointers, with Read or ReadUsingIndex.
+func (q *QuerySet[Resource]) spannerReadRows(ctx context.Context, txn *spanner.ReadOnlyTransaction, key spanner.Key, index string, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return errors.Newf("dst must be a pointer to a slice, got %T", dst)
+	}
+	rows := v.Elem()
+	elemType := rows.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.Newf("dst must be a pointer to a slice of structs, got %T", dst)
+	}
+
+	columns, err := q.columnTags()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.columnTags()")
+	}
+
+	var iter *spanner.RowIterator
+	if index == "" {
+		iter = txn.Read(ctx, string(q.Resource()), key, columns)
+	} else {
+		iter = txn.ReadUsingIndex(ctx, string(q.Resource()), index, key, columns)
+	}
+
+	if err := iter.Do(func(row *spanner.Row) error {
+		elem := reflect.New(elemType)
+		if err := row.ToStruct(elem.Interface()); err != nil {
+			return errors.Wrap(err, "spanner.Row.ToStruct()")
+		}
+
+		if isPtr {
+			rows.Set(reflect.Append(rows, elem))
+		} else {
+			rows.Set(reflect.Append(rows, elem.Elem()))
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "spanner.RowIterator.Do()")
+	}
+
+	return nil
+}
+
+// sameFields reports whether a and b hold the same fields in any order.
+func sameFields(a, b []accesstypes.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, field := range a {
+		if !slices.Contains(b, field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// forceIndexFields returns the fields of Request tagged with the forceIndex struct tag, which names a
+// secondary index with the field as its first key field, mapped to the index name.
+func forceIndexFields[Request any]() map[accesstypes.Field]string {
+	var indexes map[accesstypes.Field]string
+	for _, field := range reflect.VisibleFields(reflect.TypeFor[Request]()) {
+		name := field.Tag.Get("forceIndex")
+		if name == "" {
+			continue
+		}
+
+		if indexes == nil {
+			indexes = make(map[accesstypes.Field]string)
+		}
+		indexes[accesstypes.Field(field.Name)] = name
+	}
+
+	return indexes
+}
@@ -0,0 +1,124 @@
+package resource
+
+import (
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQuerySet_spannerReadKey(t *testing.T) {
+	t.Parallel()
+
+	byStatus := SpannerIndex{Name: "FilterResourcesByStatus", Fields: []accesstypes.Field{"Status"}, Storing: []accesstypes.Field{"Count"}}
+
+	tests := []struct {
+		name      string
+		prepare   func(q *QuerySet[filterResource])
+		wantKey   spanner.Key
+		wantIndex string
+		wantOk    bool
+	}{
+		{
+			name: "primary key",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("Status").SetKey("ID", "a")
+			},
+			wantKey: spanner.Key{"a"},
+			wantOk:  true,
+		},
+		{
+			name: "index key with stored fields",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("ID").AddField("Count").UseIndex(byStatus).SetKey("Status", "active")
+			},
+			wantKey:   spanner.Key{"active"},
+			wantIndex: "FilterResourcesByStatus",
+			wantOk:    true,
+		},
+		{
+			name: "field not in index",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("CreatedAt").UseIndex(byStatus).SetKey("Status", "active")
+			},
+		},
+		{
+			name: "key without index",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("ID").SetKey("Status", "active")
+			},
+		},
+		{
+			name: "filtered",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("Status").AddFilter(Filter{Field: "Count", Operator: EqualOperator, Value: int64(1)}).SetKey("ID", "a")
+			},
+		},
+		{
+			name: "limited",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("Status").SetLimit(1).SetKey("ID", "a")
+			},
+		},
+		{
+			name: "no key",
+			prepare: func(q *QuerySet[filterResource]) {
+				q.AddField("Status")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(SpannerDBType)
+			tt.prepare(q)
+
+			key, index, ok := q.spannerReadKey()
+			if ok != tt.wantOk {
+				t.Fatalf("QuerySet.spannerReadKey() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if diff := cmp.Diff(tt.wantKey, key); diff != "" {
+				t.Errorf("QuerySet.spannerReadKey() key mismatch (-want +got):\n%s", diff)
+			}
+			if index != tt.wantIndex {
+				t.Errorf("QuerySet.spannerReadKey() index = %q, want %q", index, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestQuerySet_SpannerStmt_forceIndex(t *testing.T) {
+	t.Parallel()
+
+	q := newFilterQuerySet(SpannerDBType).AddField("ID").
+		AddFilter(Filter{Field: "Status", Operator: EqualOperator, Value: "active"}).
+		UseIndex(SpannerIndex{Name: "FilterResourcesByStatus", Fields: []accesstypes.Field{"Status"}})
+
+	got, err := q.SpannerStmt()
+	if err != nil {
+		t.Fatalf("QuerySet.SpannerStmt() error = %v", err)
+	}
+
+	want := `SELECT Id FROM FilterResources@{FORCE_INDEX=FilterResourcesByStatus} WHERE Status = @filter0`
+	if diff := cmp.Diff(want, strings.Join(strings.Fields(got.SQL), " ")); diff != "" {
+		t.Errorf("QuerySet.SpannerStmt() SQL mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_forceIndexFields(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		ID     string `json:"id"`
+		Status string `json:"status" index:"true" forceIndex:"FilterResourcesByStatus"`
+		Count  int64  `json:"count" index:"true"`
+	}
+
+	want := map[accesstypes.Field]string{"Status": "FilterResourcesByStatus"}
+	if diff := cmp.Diff(want, forceIndexFields[request]()); diff != "" {
+		t.Errorf("forceIndexFields() mismatch (-want +got):\n%s", diff)
+	}
+}
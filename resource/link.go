@@ -144,3 +144,31 @@ func (nl *NullLink) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// Scan implements sql.Scanner for a Link stored or selected as JSON.
+func (l *Link) Scan(src any) error {
+	switch t := src.(type) {
+	case string:
+		return l.UnmarshalJSON([]byte(t))
+	case []byte:
+		return l.UnmarshalJSON(t)
+	default:
+		return errors.Newf("failed to scan %+v (type %T) as Link", src, src)
+	}
+}
+
+// Scan implements sql.Scanner for a NullLink stored or selected as JSON.
+func (nl *NullLink) Scan(src any) error {
+	switch t := src.(type) {
+	case string:
+		return nl.UnmarshalJSON([]byte(t))
+	case []byte:
+		return nl.UnmarshalJSON(t)
+	case nil:
+		nl.Valid = false
+
+		return nil
+	default:
+		return errors.Newf("failed to scan %+v (type %T) as NullLink", src, src)
+	}
+}
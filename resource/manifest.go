@@ -0,0 +1,269 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/go-playground/errors/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestVersion is the schema version written by Collection.Export and the newest version accepted by Load.
+const ManifestVersion = 1
+
+type ManifestFormat string
+
+const (
+	JSONManifest ManifestFormat = "json"
+	YAMLManifest ManifestFormat = "yaml"
+)
+
+// Manifest is a stable, versioned representation of a Collection for consumption outside of Go.
+type Manifest struct {
+	Version     int                  `json:"version"               yaml:"version"`
+	Permissions []ManifestPermission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Scopes      []ManifestScope      `json:"scopes,omitempty"      yaml:"scopes,omitempty"`
+}
+
+type ManifestPermission struct {
+	Name        accesstypes.Permission     `json:"name"                  yaml:"name"`
+	Kind        accesstypes.PermissionKind `json:"kind"                  yaml:"kind"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type ManifestScope struct {
+	Scope     accesstypes.PermissionScope `json:"scope"               yaml:"scope"`
+	Resources []ManifestResource          `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+type ManifestResource struct {
+	Name        accesstypes.Resource     `json:"name"                  yaml:"name"`
+	Description string                   `json:"description,omitempty" yaml:"description,omitempty"`
+	Permissions []accesstypes.Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Tags        []ManifestTag            `json:"tags,omitempty"        yaml:"tags,omitempty"`
+}
+
+type ManifestTag struct {
+	Name        accesstypes.Tag          `json:"name"                  yaml:"name"`
+	Description string                   `json:"description,omitempty" yaml:"description,omitempty"`
+	Permissions []accesstypes.Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Immutable   bool                     `json:"immutable,omitempty"   yaml:"immutable,omitempty"`
+}
+
+// Manifest returns the Collection as a Manifest at the current ManifestVersion.
+func (s *Collection) Manifest() *Manifest {
+	kinds := s.permissionKinds()
+	permissionDescriptions := s.PermissionDescriptions()
+
+	m := &Manifest{Version: ManifestVersion}
+	for _, perm := range s.permissions() {
+		m.Permissions = append(m.Permissions, ManifestPermission{
+			Name:        perm,
+			Kind:        kinds[perm],
+			Description: permissionDescriptions[perm],
+		})
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scopes := make([]accesstypes.PermissionScope, 0, len(s.resourceStore))
+	for scope := range s.resourceStore {
+		scopes = append(scopes, scope)
+	}
+	for scope := range s.tagStore {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	slices.Sort(scopes)
+
+	for _, scope := range scopes {
+		resources := make([]accesstypes.Resource, 0, len(s.resourceStore[scope]))
+		for res := range s.resourceStore[scope] {
+			resources = append(resources, res)
+		}
+		for res := range s.tagStore[scope] {
+			if !slices.Contains(resources, res) {
+				resources = append(resources, res)
+			}
+		}
+		slices.Sort(resources)
+
+		manifestScope := ManifestScope{Scope: scope}
+		for _, res := range resources {
+			manifestResource := ManifestResource{
+				Name:        res,
+				Description: s.resourceDescriptions[res],
+				Permissions: sortedPermissions(s.resourceStore[scope][res]),
+			}
+
+			tags := make([]accesstypes.Tag, 0, len(s.tagStore[scope][res]))
+			for tag := range s.tagStore[scope][res] {
+				tags = append(tags, tag)
+			}
+			slices.Sort(tags)
+
+			for _, tag := range tags {
+				_, immutable := s.immutableFields[scope][res][tag]
+				manifestResource.Tags = append(manifestResource.Tags, ManifestTag{
+					Name:        tag,
+					Description: s.resourceDescriptions[res.ResourceWithTag(tag)],
+					Permissions: sortedPermissions(s.tagStore[scope][res][tag]),
+					Immutable:   immutable,
+				})
+			}
+
+			manifestScope.Resources = append(manifestScope.Resources, manifestResource)
+		}
+
+		m.Scopes = append(m.Scopes, manifestScope)
+	}
+
+	return m
+}
+
+// Export writes the Collection to w as a Manifest in the requested format.
+func (s *Collection) Export(w io.Writer, format ManifestFormat) error {
+	m := s.Manifest()
+
+	switch format {
+	case JSONManifest:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(m); err != nil {
+			return errors.Wrap(err, "json.Encoder.Encode()")
+		}
+	case YAMLManifest:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(m); err != nil {
+			return errors.Wrap(err, "yaml.Encoder.Encode()")
+		}
+		if err := enc.Close(); err != nil {
+			return errors.Wrap(err, "yaml.Encoder.Close()")
+		}
+	default:
+		return errors.Newf("unsupported manifest format %q", format)
+	}
+
+	return nil
+}
+
+// Load reads a Manifest in the requested format from r and returns it as a Collection.
+// The returned Collection records registrations regardless of the collect_resource_permissions build tag.
+func Load(r io.Reader, format ManifestFormat) (*Collection, error) {
+	var m Manifest
+
+	switch format {
+	case JSONManifest:
+		if err := json.NewDecoder(r).Decode(&m); err != nil {
+			return nil, errors.Wrap(err, "json.Decoder.Decode()")
+		}
+	case YAMLManifest:
+		if err := yaml.NewDecoder(r).Decode(&m); err != nil {
+			return nil, errors.Wrap(err, "yaml.Decoder.Decode()")
+		}
+	default:
+		return nil, errors.Newf("unsupported manifest format %q", format)
+	}
+
+	c, err := m.Collection()
+	if err != nil {
+		return nil, errors.Wrap(err, "Manifest.Collection()")
+	}
+
+	return c, nil
+}
+
+// Collection builds a Collection from the Manifest.
+func (m *Manifest) Collection() (*Collection, error) {
+	if m.Version < 1 || m.Version > ManifestVersion {
+		return nil, errors.Newf("unsupported manifest version %d, expected 1 through %d", m.Version, ManifestVersion)
+	}
+
+	c := NewCollection(RuntimeCollection())
+
+	for _, perm := range m.Permissions {
+		switch perm.Kind {
+		case accesstypes.ViewingPermissionKind, accesstypes.MutatingPermissionKind, accesstypes.ActionPermissionKind:
+			c.loadedPermissionKinds[perm.Name] = perm.Kind
+		case "":
+		default:
+			return nil, errors.Newf("invalid kind %q for permission %q", perm.Kind, perm.Name)
+		}
+
+		if perm.Description != "" {
+			c.permissionDescriptions[perm.Name] = perm.Description
+		}
+	}
+
+	for _, scope := range m.Scopes {
+		for _, res := range scope.Resources {
+			for _, perm := range res.Permissions {
+				if err := c.addResource(false, scope.Scope, perm, res.Name); err != nil {
+					return nil, err
+				}
+			}
+
+			if res.Description != "" {
+				c.resourceDescriptions[res.Name] = res.Description
+			}
+
+			if len(res.Tags) == 0 {
+				continue
+			}
+
+			if c.tagStore[scope.Scope] == nil {
+				c.tagStore[scope.Scope] = make(tagStore)
+			}
+			if c.tagStore[scope.Scope][res.Name] == nil {
+				c.tagStore[scope.Scope][res.Name] = make(map[accesstypes.Tag][]accesstypes.Permission, len(res.Tags))
+			}
+
+			for _, tag := range res.Tags {
+				// a tag without permissions is still recorded, so that it is exported again
+				permissions := c.tagStore[scope.Scope][res.Name][tag.Name]
+				for _, permission := range tag.Permissions {
+					if slices.Contains(permissions, permission) {
+						return nil, errors.Newf("found existing mapping between tag (%s) and permission (%s) under resource (%s)", tag.Name, permission, res.Name)
+					}
+
+					if permission != accesstypes.NullPermission {
+						permissions = append(permissions, permission)
+					}
+				}
+				c.tagStore[scope.Scope][res.Name][tag.Name] = permissions
+
+				if tag.Description != "" {
+					c.resourceDescriptions[res.Name.ResourceWithTag(tag.Name)] = tag.Description
+				}
+
+				if tag.Immutable {
+					if c.immutableFields[scope.Scope] == nil {
+						c.immutableFields[scope.Scope] = make(immutableFieldMap)
+					}
+					if c.immutableFields[scope.Scope][res.Name] == nil {
+						c.immutableFields[scope.Scope][res.Name] = make(map[accesstypes.Tag]struct{})
+					}
+					c.immutableFields[scope.Scope][res.Name][tag.Name] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func sortedPermissions(perms []accesstypes.Permission) []accesstypes.Permission {
+	if len(perms) == 0 {
+		return nil
+	}
+
+	perms = slices.Clone(perms)
+	slices.Sort(perms)
+
+	return perms
+}
@@ -0,0 +1,171 @@
+package resource
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCollection_Export(t *testing.T) {
+	t.Parallel()
+
+	c := NewCollection(RuntimeCollection())
+	rSet, err := NewResourceSet[AResource, catalogRequest]()
+	if err != nil {
+		t.Fatalf("NewResourceSet() error = %v", err)
+	}
+	if err := AddResources(c, accesstypes.GlobalPermissionScope, rSet); err != nil {
+		t.Fatalf("AddResources() error = %v", err)
+	}
+
+	want := &Manifest{
+		Version: ManifestVersion,
+		Permissions: []ManifestPermission{
+			{Name: accesstypes.Update, Kind: accesstypes.MutatingPermissionKind, Description: "Update an existing resource"},
+		},
+		Scopes: []ManifestScope{
+			{
+				Scope: accesstypes.GlobalPermissionScope,
+				Resources: []ManifestResource{
+					{
+						Name:        "AResources",
+						Permissions: []accesstypes.Permission{accesstypes.Update},
+						Tags: []ManifestTag{
+							{Name: "field1", Description: "The first field", Permissions: []accesstypes.Permission{accesstypes.Update}},
+							{Name: "field2", Permissions: []accesstypes.Permission{accesstypes.Update}, Immutable: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		format ManifestFormat
+	}{
+		{name: "json", format: JSONManifest},
+		{name: "yaml", format: YAMLManifest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := c.Export(&buf, tt.format); err != nil {
+				t.Fatalf("Collection.Export() error = %v", err)
+			}
+
+			loaded, err := Load(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if diff := cmp.Diff(want, loaded.Manifest()); diff != "" {
+				t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+			}
+			if !loaded.IsResourceImmutable(accesstypes.GlobalPermissionScope, "AResources.field2") {
+				t.Errorf("Collection.IsResourceImmutable() = false, want true")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  ManifestFormat
+		input   string
+		wantErr bool
+	}{
+		{name: "yaml", format: YAMLManifest, input: "version: 1\nscopes:\n  - scope: global\n    resources:\n      - name: Users\n        permissions: [Read]\n"},
+		{name: "unsupported version", format: JSONManifest, input: `{"version": 2}`, wantErr: true},
+		{name: "missing version", format: JSONManifest, input: `{}`, wantErr: true},
+		{name: "duplicate permission", format: JSONManifest, input: `{"version": 1, "scopes": [{"scope": "global", "resources": [{"name": "Users", "permissions": ["Read", "Read"]}]}]}`, wantErr: true},
+		{name: "duplicate tag permission", format: JSONManifest, input: `{"version": 1, "scopes": [{"scope": "global", "resources": [{"name": "Users", "tags": [{"name": "ssn", "permissions": ["Read", "Read"]}]}]}]}`, wantErr: true},
+		{name: "invalid permission kind", format: JSONManifest, input: `{"version": 1, "permissions": [{"name": "Read", "kind": "reading"}]}`, wantErr: true},
+		{name: "unsupported format", format: "xml", input: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Load(strings.NewReader(tt.input), tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManifest_Collection_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	want := &Manifest{
+		Version: ManifestVersion,
+		Permissions: []ManifestPermission{
+			{Name: "Approve", Kind: accesstypes.ActionPermissionKind, Description: "Approve an order"},
+			{Name: accesstypes.Read, Kind: accesstypes.ViewingPermissionKind, Description: "Read a resource"},
+		},
+		Scopes: []ManifestScope{
+			{
+				Scope: accesstypes.GlobalPermissionScope,
+				Resources: []ManifestResource{
+					{
+						Name:        "Orders",
+						Permissions: []accesstypes.Permission{"Approve", accesstypes.Read},
+						Tags: []ManifestTag{
+							{Name: "notes"},
+							{Name: "total", Permissions: []accesstypes.Permission{accesstypes.Read}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	input := *want
+	input.Scopes = []ManifestScope{
+		{
+			Scope: accesstypes.GlobalPermissionScope,
+			Resources: []ManifestResource{
+				{
+					Name:        "Orders",
+					Permissions: []accesstypes.Permission{"Approve", accesstypes.Read},
+					Tags: []ManifestTag{
+						{Name: "notes", Permissions: []accesstypes.Permission{}},
+						{Name: "total", Permissions: []accesstypes.Permission{accesstypes.NullPermission, accesstypes.Read}},
+					},
+				},
+			},
+		},
+	}
+
+	c, err := input.Collection()
+	if err != nil {
+		t.Fatalf("Manifest.Collection() error = %v", err)
+	}
+
+	if diff := cmp.Diff(want, c.Manifest()); diff != "" {
+		t.Errorf("Collection.Manifest() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, format := range []ManifestFormat{JSONManifest, YAMLManifest} {
+		var buf bytes.Buffer
+		if err := c.Export(&buf, format); err != nil {
+			t.Fatalf("Collection.Export() error = %v", err)
+		}
+
+		loaded, err := Load(&buf, format)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		if diff := cmp.Diff(want, loaded.Manifest()); diff != "" {
+			t.Errorf("Load(Collection.Export(%s)) mismatch (-want +got):\n%s", format, diff)
+		}
+	}
+}
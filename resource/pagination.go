@@ -0,0 +1,480 @@
+package resource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/cccteam/spxscan"
+	"github.com/go-playground/errors/v5"
+	"google.golang.org/api/iterator"
+)
+
+// MaxPageSize is the largest limit accepted by the limit query parameter.
+const MaxPageSize = 1000
+
+// Cursor is an opaque token marking the position after the last row of a page.
+type Cursor string
+
+// Page describes a page of results returned by SpannerListPage.
+type Page struct {
+	// NextCursor is the cursor for the following page. It is empty when there are no more rows, and for
+	// searches, which are paginated with SetOffset.
+	NextCursor Cursor `json:"nextCursor,omitempty"`
+	// HasMore reports whether there are rows after this page.
+	HasMore bool `json:"hasMore"`
+	// Total is the number of rows matching the query, set when requested with SetIncludeTotal.
+	Total *int64 `json:"total,omitempty"`
+}
+
+type cursorData struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// SetLimit limits the number of rows returned. A limit of 0 returns all rows.
+func (q *QuerySet[Resource]) SetLimit(limit int) *QuerySet[Resource] {
+	q.limit = limit
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Limit() int {
+	return q.limit
+}
+
+// SetOffset skips the first offset rows. It requires a limit.
+func (q *QuerySet[Resource]) SetOffset(offset int) *QuerySet[Resource] {
+	q.offset = offset
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Offset() int {
+	return q.offset
+}
+
+// SetCursor resumes the query after the row identified by cursor, as returned in Page.NextCursor.
+// Cursors are only valid for a query with the same sort.
+func (q *QuerySet[Resource]) SetCursor(cursor Cursor) *QuerySet[Resource] {
+	q.cursor = cursor
+
+	return q
+}
+
+// SetIncludeTotal requests the total number of matching rows in the Page returned by SpannerListPage.
+func (q *QuerySet[Resource]) SetIncludeTotal(includeTotal bool) *QuerySet[Resource] {
+	q.total = includeTotal
+
+	return q
+}
+
+// SpannerListPage lists a single page of rows into dst, which must be a pointer to a slice of structs
+// with fields named after the sort and primary key fields. A limit must be set.
+//
+// When a total is requested txn must be able to run more than one query.
+func (q *QuerySet[Resource]) SpannerListPage(ctx context.Context, txn *spanner.ReadOnlyTransaction, dst any) (*Page, error) {
+	if q.rMeta.dbType != SpannerDBType {
+		return nil, errors.Newf("can only use SpannerListPage() with dbType %s, got %s", SpannerDBType, q.rMeta.dbType)
+	}
+
+	if err := q.checkTimestampBound(); err != nil {
+		return nil, err
+	}
+
+	if q.limit <= 0 {
+		return nil, errors.New("SpannerListPage() requires a limit")
+	}
+
+	if len(q.rMeta.primaryKeys) == 0 {
+		return nil, errors.Newf("SpannerListPage() requires the primary key of %s, which must implement PrimaryKeyer", q.Resource())
+	}
+
+	rows := reflect.ValueOf(dst)
+	if rows.Kind() != reflect.Pointer || rows.Elem().Kind() != reflect.Slice {
+		return nil, errors.Newf("dst must be a pointer to a slice, got %T", dst)
+	}
+	rows = rows.Elem()
+
+	stmt, err := q.spannerStmt(q.limit + 1)
+	if err != nil {
+		return nil, errors.Wrap(err, "QuerySet.spannerStmt()")
+	}
+
+	if err := spxscan.Select(ctx, txn, dst, stmt); err != nil {
+		return nil, errors.Wrap(err, "spxscan.Select()")
+	}
+
+	page, err := q.trimPage(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.spannerIncludes(ctx, txn, dst); err != nil {
+		return nil, err
+	}
+
+	if q.total {
+		total, err := q.spannerCount(ctx, txn)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// trimPage trims rows, fetched with a limit of one more than the page size, to the page size and
+// returns the Page for it.
+func (q *QuerySet[Resource]) trimPage(rows reflect.Value) (*Page, error) {
+	page := &Page{}
+	if rows.Len() <= q.limit {
+		return page, nil
+	}
+
+	rows.Set(rows.Slice(0, q.limit))
+	page.HasMore = true
+
+	if len(q.searches) == 0 {
+		cursor, err := q.encodeCursor(rows.Index(q.limit - 1))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+
+	return page, nil
+}
+
+func (q *QuerySet[Resource]) spannerCount(ctx context.Context, txn *spanner.ReadOnlyTransaction) (int64, error) {
+	where, err := q.matchWhere()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := spanner.NewStatement(fmt.Sprintf("SELECT COUNT(*) FROM %s %s", q.spannerTable(), where.Sql))
+	maps.Insert(stmt.Params, maps.All(where.Params))
+
+	iter := txn.Query(ctx, stmt)
+	defer iter.Stop()
+
+	row, err := iter.Next()
+	if err != nil {
+		if errors.Is(err, iterator.Done) {
+			return 0, nil
+		}
+
+		return 0, errors.Wrap(err, "spanner.RowIterator.Next()")
+	}
+
+	var total int64
+	if err := row.Columns(&total); err != nil {
+		return 0, errors.Wrap(err, "spanner.Row.Columns()")
+	}
+
+	return total, nil
+}
+
+// matchWhere returns the WHERE clause selecting every row that matches the keys, filters and searches,
+// ignoring pagination.
+func (q *QuerySet[Resource]) matchWhere() (Statement, error) {
+	if len(q.searches) == 0 {
+		where, err := q.Where()
+		if err != nil {
+			return Statement{}, errors.Wrap(err, "QuerySet.Where()")
+		}
+
+		return where, nil
+	}
+
+	where, search, _, err := q.searchWhere()
+	if err != nil {
+		return Statement{}, err
+	}
+	maps.Insert(where.Params, maps.All(search.Params))
+
+	return where, nil
+}
+
+// order returns the effective sort. Paginated queries are additionally ordered by
+// the primary key so that every row has a stable position.
+func (q *QuerySet[Resource]) order() []SortField {
+	if q.limit == 0 && q.cursor == "" {
+		return q.sort
+	}
+
+	order := slices.Clone(q.sort)
+	for _, key := range q.rMeta.primaryKeys {
+		if !slices.ContainsFunc(order, func(s SortField) bool { return s.Field == key }) {
+			order = append(order, SortField{Field: key, Direction: Ascending})
+		}
+	}
+
+	return order
+}
+
+// pageWhere returns the WHERE clause including the condition that resumes after the cursor.
+func (q *QuerySet[Resource]) pageWhere() (Statement, error) {
+	where, err := q.Where()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.Where()")
+	}
+
+	if q.cursor == "" {
+		return where, nil
+	}
+
+	if len(q.rMeta.primaryKeys) == 0 {
+		return Statement{}, errors.Newf("cursor pagination requires the primary key of %s, which must implement PrimaryKeyer", q.Resource())
+	}
+
+	order := q.order()
+	values, err := q.decodeCursor(order)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	cursor, err := cursorCondition(q.rMeta.dbType, q.rMeta.fieldMap, order, values)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	if where.Sql == "" {
+		where = Statement{Sql: "WHERE " + cursor.Sql, Params: make(map[string]any, len(cursor.Params))}
+	} else {
+		where.Sql += " AND " + cursor.Sql
+	}
+	maps.Insert(where.Params, maps.All(cursor.Params))
+
+	return where, nil
+}
+
+// cursorCondition expands the keyset comparison of order against values, which can mix
+// ascending and descending fields, into (a > @cursor0) OR (a = @cursor0 AND b > @cursor1) ...
+// NULLs sort before every other value (see orderBy), so a NULL cursor value is followed by every
+// non-NULL value in ascending order and by nothing in descending order.
+func cursorCondition(dbType DBType, fieldMap map[accesstypes.Field]cacheEntry, order []SortField, values []any) (Statement, error) {
+	columns := make([]string, 0, len(order))
+	for _, s := range order {
+		c, ok := fieldMap[s.Field]
+		if !ok {
+			return Statement{}, errors.Newf("field %s not found in struct", s.Field)
+		}
+
+		switch dbType {
+		case SpannerDBType:
+			columns = append(columns, c.tag)
+		case PostgresDBType, SQLiteDBType:
+			columns = append(columns, fmt.Sprintf(`"%s"`, c.tag))
+		default:
+			return Statement{}, errors.Newf("unsupported dbType: %s", dbType)
+		}
+	}
+
+	params := make(map[string]any, len(values))
+	ors := make([]string, 0, len(order))
+	for i, s := range order {
+		ands := make([]string, 0, i+1)
+		for j := range i {
+			if isNullValue(values[j]) {
+				ands = append(ands, fmt.Sprintf("%s IS NULL", columns[j]))
+			} else {
+				ands = append(ands, fmt.Sprintf("%s = @cursor%d", columns[j], j))
+			}
+		}
+
+		switch {
+		case s.Direction == Descending && isNullValue(values[i]):
+			continue
+		case s.Direction == Descending && isNullable(values[i]):
+			ands = append(ands, fmt.Sprintf("(%s < @cursor%d OR %s IS NULL)", columns[i], i, columns[i]))
+		case s.Direction == Descending:
+			ands = append(ands, fmt.Sprintf("%s < @cursor%d", columns[i], i))
+		case isNullValue(values[i]):
+			ands = append(ands, fmt.Sprintf("%s IS NOT NULL", columns[i]))
+		default:
+			ands = append(ands, fmt.Sprintf("%s > @cursor%d", columns[i], i))
+		}
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+
+	for i, value := range values {
+		if !isNullValue(value) {
+			params[fmt.Sprintf("cursor%d", i)] = value
+		}
+	}
+
+	if len(ors) == 0 {
+		return Statement{Sql: "FALSE", Params: params}, nil
+	}
+
+	return Statement{
+		Sql:    "(" + strings.Join(ors, " OR ") + ")",
+		Params: params,
+	}, nil
+}
+
+// isNullValue reports whether v is a nil pointer or a null value of a nullable type such as spanner.NullString.
+func isNullValue(v any) bool {
+	switch n := v.(type) {
+	case nil:
+		return true
+	case interface{ IsNull() bool }:
+		return n.IsNull()
+	case interface{ IsNil() bool }:
+		return n.IsNil()
+	}
+
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// isNullable reports whether v is of a type which can hold a null value.
+func isNullable(v any) bool {
+	switch v.(type) {
+	case nil, interface{ IsNull() bool }, interface{ IsNil() bool }:
+		return true
+	}
+
+	return reflect.TypeOf(v).Kind() == reflect.Pointer
+}
+
+func (q *QuerySet[Resource]) limitClause(fetch int) (string, error) {
+	if q.offset < 0 || fetch < 0 {
+		return "", errors.New("limit and offset can not be negative")
+	}
+
+	if fetch == 0 {
+		if q.offset > 0 {
+			return "", errors.New("offset requires a limit")
+		}
+
+		return "", nil
+	}
+
+	if q.offset > 0 {
+		return fmt.Sprintf("LIMIT %d OFFSET %d", fetch, q.offset), nil
+	}
+
+	return fmt.Sprintf("LIMIT %d", fetch), nil
+}
+
+func (q *QuerySet[Resource]) encodeCursor(row reflect.Value) (Cursor, error) {
+	for row.Kind() == reflect.Pointer {
+		row = row.Elem()
+	}
+
+	order := q.order()
+	data := cursorData{Sort: sortSignature(order), Values: make([]json.RawMessage, 0, len(order))}
+	for _, s := range order {
+		field := row.FieldByName(string(s.Field))
+		if !field.IsValid() {
+			return "", errors.Newf("field %s not found in %s", s.Field, row.Type())
+		}
+
+		value, err := json.Marshal(field.Interface())
+		if err != nil {
+			return "", errors.Wrap(err, "json.Marshal()")
+		}
+		data.Values = append(data.Values, value)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.Wrap(err, "json.Marshal()")
+	}
+
+	return Cursor(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+func (q *QuerySet[Resource]) decodeCursor(order []SortField) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(string(q.cursor))
+	if err != nil {
+		return nil, httpio.NewBadRequestMessage("invalid cursor")
+	}
+
+	var data cursorData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, httpio.NewBadRequestMessage("invalid cursor")
+	}
+
+	if data.Sort != sortSignature(order) || len(data.Values) != len(order) {
+		return nil, httpio.NewBadRequestMessage("cursor does not match the requested sort")
+	}
+
+	resourceType := reflect.TypeFor[Resource]()
+	values := make([]any, 0, len(order))
+	for i, s := range order {
+		c, ok := q.rMeta.fieldMap[s.Field]
+		if !ok {
+			return nil, errors.Newf("field %s not found in struct", s.Field)
+		}
+
+		value := reflect.New(resourceType.Field(c.index).Type)
+		if err := json.Unmarshal(data.Values[i], value.Interface()); err != nil {
+			return nil, httpio.NewBadRequestMessage("invalid cursor")
+		}
+		values = append(values, value.Elem().Interface())
+	}
+
+	return values, nil
+}
+
+func sortSignature(order []SortField) string {
+	terms := make([]string, 0, len(order))
+	for _, s := range order {
+		if s.Direction == Descending {
+			terms = append(terms, "-"+string(s.Field))
+		} else {
+			terms = append(terms, string(s.Field))
+		}
+	}
+
+	return strings.Join(terms, ",")
+}
+
+// parsePageParams parses the limit, offset, cursor and total query parameters.
+func parsePageParams(queryParams url.Values) (limit, offset int, cursor Cursor, total bool, err error) {
+	if v := queryParams.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxPageSize {
+			return 0, 0, "", false, httpio.NewBadRequestMessagef("limit must be between 1 and %d", MaxPageSize)
+		}
+	}
+
+	if v := queryParams.Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, "", false, httpio.NewBadRequestMessage("offset must be a non-negative integer")
+		}
+		if limit == 0 {
+			return 0, 0, "", false, httpio.NewBadRequestMessage("offset requires a limit")
+		}
+	}
+
+	cursor = Cursor(queryParams.Get("cursor"))
+	if cursor != "" && offset > 0 {
+		return 0, 0, "", false, httpio.NewBadRequestMessage("cursor and offset can not be used together")
+	}
+
+	if v := queryParams.Get("total"); v != "" {
+		total, err = strconv.ParseBool(v)
+		if err != nil {
+			return 0, 0, "", false, httpio.NewBadRequestMessage("total must be true or false")
+		}
+	}
+
+	return limit, offset, cursor, total, nil
+}
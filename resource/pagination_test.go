@@ -0,0 +1,308 @@
+package resource
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newFilterQuerySet(dbType DBType) *QuerySet[filterResource] {
+	return NewQuerySet(&ResourceMetadata[filterResource]{
+		fieldMap:    structTags(reflect.TypeFor[filterResource](), string(dbType)),
+		primaryKeys: primaryKeyFields(reflect.TypeFor[filterResource]()),
+		dbType:      dbType,
+	})
+}
+
+func TestQuerySet_pageWhere(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cursor, err := newFilterQuerySet(SpannerDBType).
+		SetLimit(10).
+		OrderBy(SortField{Field: "CreatedAt", Direction: Descending}).
+		encodeCursor(reflect.ValueOf(&filterRequest{ID: "abc", CreatedAt: createdAt}))
+	if err != nil {
+		t.Fatalf("QuerySet.encodeCursor() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dbType  DBType
+		sort    []SortField
+		noKeys  bool
+		cursor  Cursor
+		want    Statement
+		wantErr bool
+	}{
+		{
+			name:   "no cursor",
+			dbType: SpannerDBType,
+			want:   Statement{Sql: "WHERE STARTS_WITH(Status, @filter0)", Params: map[string]any{"filter0": "a"}},
+		},
+		{
+			name:   "spanner",
+			dbType: SpannerDBType,
+			sort:   []SortField{{Field: "CreatedAt", Direction: Descending}},
+			cursor: cursor,
+			want: Statement{
+				Sql:    "WHERE STARTS_WITH(Status, @filter0) AND ((CreatedAt < @cursor0) OR (CreatedAt = @cursor0 AND Id > @cursor1))",
+				Params: map[string]any{"filter0": "a", "cursor0": createdAt, "cursor1": "abc"},
+			},
+		},
+		{
+			name:   "postgres",
+			dbType: PostgresDBType,
+			sort:   []SortField{{Field: "CreatedAt", Direction: Descending}},
+			cursor: cursor,
+			want: Statement{
+				Sql:    `WHERE "status" LIKE @filter0 AND (("created_at" < @cursor0) OR ("created_at" = @cursor0 AND "id" > @cursor1))`,
+				Params: map[string]any{"filter0": "a%", "cursor0": createdAt, "cursor1": "abc"},
+			},
+		},
+		{
+			name:    "cursor for another sort",
+			dbType:  SpannerDBType,
+			sort:    []SortField{{Field: "Status"}},
+			cursor:  cursor,
+			wantErr: true,
+		},
+		{
+			name:    "malformed cursor",
+			dbType:  SpannerDBType,
+			cursor:  "not a cursor",
+			wantErr: true,
+		},
+		{
+			name:    "no primary key",
+			dbType:  SpannerDBType,
+			noKeys:  true,
+			sort:    []SortField{{Field: "CreatedAt", Direction: Descending}},
+			cursor:  cursor,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(tt.dbType)
+			if tt.noKeys {
+				q.rMeta.primaryKeys = nil
+			}
+			q.
+				AddFilter(Filter{Field: "Status", Operator: PrefixOperator, Value: "a"}).
+				OrderBy(tt.sort...).
+				SetLimit(10).
+				SetCursor(tt.cursor)
+
+			got, err := q.pageWhere()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuerySet.pageWhere() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.pageWhere() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_trimPage(t *testing.T) {
+	t.Parallel()
+
+	cursor, err := newFilterQuerySet(SpannerDBType).SetLimit(2).encodeCursor(reflect.ValueOf(filterRequest{ID: "b"}))
+	if err != nil {
+		t.Fatalf("QuerySet.encodeCursor() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		rows     []filterRequest
+		search   bool
+		wantRows []filterRequest
+		want     *Page
+	}{
+		{
+			name:     "last page",
+			rows:     []filterRequest{{ID: "a"}, {ID: "b"}},
+			wantRows: []filterRequest{{ID: "a"}, {ID: "b"}},
+			want:     &Page{},
+		},
+		{
+			name:     "more rows",
+			rows:     []filterRequest{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			wantRows: []filterRequest{{ID: "a"}, {ID: "b"}},
+			want:     &Page{NextCursor: cursor, HasMore: true},
+		},
+		{
+			name:     "search with more rows",
+			rows:     []filterRequest{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			search:   true,
+			wantRows: []filterRequest{{ID: "a"}, {ID: "b"}},
+			want:     &Page{HasMore: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(SpannerDBType).SetLimit(2)
+			if tt.search {
+				q.SetSearchParam(NewSearchSet(SubString, "Status", "a"))
+			}
+
+			rows := tt.rows
+			got, err := q.trimPage(reflect.ValueOf(&rows).Elem())
+			if err != nil {
+				t.Fatalf("QuerySet.trimPage() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.trimPage() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRows, rows); diff != "" {
+				t.Errorf("QuerySet.trimPage() rows mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_cursorCondition(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fieldMap := structTags(reflect.TypeFor[filterResource](), string(SpannerDBType))
+
+	tests := []struct {
+		name   string
+		order  []SortField
+		values []any
+		want   Statement
+	}{
+		{
+			name:   "descending nullable",
+			order:  []SortField{{Field: "DeletedAt", Direction: Descending}, {Field: "ID"}},
+			values: []any{&deletedAt, "abc"},
+			want: Statement{
+				Sql:    "(((DeletedAt < @cursor0 OR DeletedAt IS NULL)) OR (DeletedAt = @cursor0 AND Id > @cursor1))",
+				Params: map[string]any{"cursor0": &deletedAt, "cursor1": "abc"},
+			},
+		},
+		{
+			name:   "descending null",
+			order:  []SortField{{Field: "DeletedAt", Direction: Descending}, {Field: "ID"}},
+			values: []any{(*time.Time)(nil), "abc"},
+			want: Statement{
+				Sql:    "((DeletedAt IS NULL AND Id > @cursor1))",
+				Params: map[string]any{"cursor1": "abc"},
+			},
+		},
+		{
+			name:   "ascending null",
+			order:  []SortField{{Field: "DeletedAt"}, {Field: "ID"}},
+			values: []any{(*time.Time)(nil), "abc"},
+			want: Statement{
+				Sql:    "((DeletedAt IS NOT NULL) OR (DeletedAt IS NULL AND Id > @cursor1))",
+				Params: map[string]any{"cursor1": "abc"},
+			},
+		},
+		{
+			name:   "last row",
+			order:  []SortField{{Field: "DeletedAt", Direction: Descending}},
+			values: []any{(*time.Time)(nil)},
+			want:   Statement{Sql: "FALSE", Params: map[string]any{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cursorCondition(SpannerDBType, fieldMap, tt.order, tt.values)
+			if err != nil {
+				t.Fatalf("cursorCondition() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("cursorCondition() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_limitClause(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		limit       int
+		offset      int
+		want        string
+		wantOrderBy string
+		wantErr     bool
+	}{
+		{name: "unlimited"},
+		{name: "limit", limit: 10, want: "LIMIT 10", wantOrderBy: "ORDER BY Id ASC"},
+		{name: "limit and offset", limit: 10, offset: 20, want: "LIMIT 10 OFFSET 20", wantOrderBy: "ORDER BY Id ASC"},
+		{name: "offset without limit", offset: 20, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(SpannerDBType).SetLimit(tt.limit).SetOffset(tt.offset)
+
+			got, err := q.limitClause(q.Limit())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuerySet.limitClause() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("QuerySet.limitClause() = %q, want %q", got, tt.want)
+			}
+
+			orderBy, err := q.OrderByClause()
+			if err != nil {
+				t.Fatalf("QuerySet.OrderByClause() error = %v", err)
+			}
+			if orderBy != tt.wantOrderBy {
+				t.Errorf("QuerySet.OrderByClause() = %q, want %q", orderBy, tt.wantOrderBy)
+			}
+		})
+	}
+}
+
+func Test_parsePageParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		wantLimit  int
+		wantOffset int
+		wantCursor Cursor
+		wantTotal  bool
+		wantErr    bool
+	}{
+		{name: "none", query: url.Values{}},
+		{name: "offset", query: url.Values{"limit": {"25"}, "offset": {"50"}, "total": {"true"}}, wantLimit: 25, wantOffset: 50, wantTotal: true},
+		{name: "cursor", query: url.Values{"limit": {"25"}, "cursor": {"abc"}}, wantLimit: 25, wantCursor: "abc"},
+		{name: "limit too large", query: url.Values{"limit": {"1001"}}, wantErr: true},
+		{name: "limit zero", query: url.Values{"limit": {"0"}}, wantErr: true},
+		{name: "offset without limit", query: url.Values{"offset": {"5"}}, wantErr: true},
+		{name: "cursor and offset", query: url.Values{"limit": {"5"}, "offset": {"5"}, "cursor": {"abc"}}, wantErr: true},
+		{name: "invalid total", query: url.Values{"total": {"maybe"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limit, offset, cursor, total, err := parsePageParams(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePageParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset || cursor != tt.wantCursor || total != tt.wantTotal {
+				t.Errorf("parsePageParams() = (%d, %d, %q, %v), want (%d, %d, %q, %v)", limit, offset, cursor, total, tt.wantLimit, tt.wantOffset, tt.wantCursor, tt.wantTotal)
+			}
+		})
+	}
+}
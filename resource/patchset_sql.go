@@ -0,0 +1,458 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+// constraintKind classifies an integrity constraint violation reported by the database.
+type constraintKind int
+
+const (
+	uniqueViolation constraintKind = iota + 1
+	foreignKeyViolation
+	notNullViolation
+	checkViolation
+)
+
+// sqlTx runs the statements of a PatchSet in a caller-supplied transaction of a database
+// that shares Postgres syntax.
+type sqlTx interface {
+	dbType() DBType
+	get(ctx context.Context, stmt Statement, dst any) (found bool, err error)
+	exec(ctx context.Context, stmt Statement) (rowsAffected int64, err error)
+	// constraint reports whether err is an integrity constraint violation, and names
+	// the constraint or column involved
+	constraint(err error) (kind constraintKind, name string, ok bool)
+}
+
+// PostgresApply writes the PatchSet using tx. The caller owns the transaction and is responsible for
+// committing or rolling it back. When change tracking is enabled, a DataChangeEvent is inserted in the same transaction.
+func (p *PatchSet[Resource]) PostgresApply(ctx context.Context, tx PostgresTx, eventSource ...string) error {
+	return p.sqlApply(ctx, postgresTx{tx}, eventSource...)
+}
+
+// PostgresInsertOrUpdate inserts the PatchSet, or updates the existing row when one with the same primary key exists.
+func (p *PatchSet[Resource]) PostgresInsertOrUpdate(ctx context.Context, tx PostgresTx, eventSource ...string) error {
+	return p.sqlInsertOrUpdate(ctx, postgresTx{tx}, eventSource...)
+}
+
+// SQLiteApply writes the PatchSet using tx. The caller owns the transaction and is responsible for
+// committing or rolling it back. When change tracking is enabled, a DataChangeEvent is inserted in the same transaction.
+func (p *PatchSet[Resource]) SQLiteApply(ctx context.Context, tx SQLiteTx, eventSource ...string) error {
+	return p.sqlApply(ctx, sqliteTx{tx}, eventSource...)
+}
+
+// SQLiteInsertOrUpdate inserts the PatchSet, or updates the existing row when one with the same primary key exists.
+func (p *PatchSet[Resource]) SQLiteInsertOrUpdate(ctx context.Context, tx SQLiteTx, eventSource ...string) error {
+	return p.sqlInsertOrUpdate(ctx, sqliteTx{tx}, eventSource...)
+}
+
+func (p *PatchSet[Resource]) sqlApply(ctx context.Context, tx sqlTx, eventSource ...string) error {
+	if tx.dbType() != p.querySet.rMeta.dbType {
+		return errors.Newf("can not apply %s with dbType %s to %s", p.Resource(), p.querySet.rMeta.dbType, tx.dbType())
+	}
+
+	switch p.patchType {
+	case CreatePatchType:
+		return p.sqlInsert(ctx, tx, eventSource...)
+	case UpdatePatchType:
+		return p.sqlUpdate(ctx, tx, eventSource...)
+	case DeletePatchType:
+		return p.sqlDelete(ctx, tx, eventSource...)
+	default:
+		return errors.Newf("PatchType %s not supported", p.patchType)
+	}
+}
+
+func (p *PatchSet[Resource]) sqlInsert(ctx context.Context, tx sqlTx, eventSource ...string) error {
+	event, err := p.validateEventSource(eventSource)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := p.sqlInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.sqlExec(ctx, tx, stmt); err != nil {
+		return err
+	}
+
+	if p.querySet.rMeta.trackChanges {
+		changeSet, err := p.insertChangeSet()
+		if err != nil {
+			return err
+		}
+
+		if err := p.sqlInsertDataChangeEvent(ctx, tx, event, changeSet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *PatchSet[Resource]) sqlUpdate(ctx context.Context, tx sqlTx, eventSource ...string) error {
+	event, err := p.validateEventSource(eventSource)
+	if err != nil {
+		return err
+	}
+
+	var changeSet map[accesstypes.Field]DiffElem
+	if p.querySet.rMeta.trackChanges {
+		old, found, err := p.sqlReadForUpdate(ctx, tx, p.querySet)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return httpio.NewNotFoundMessagef("%s (%s) not found", p.Resource(), p.PrimaryKey().String())
+		}
+
+		changeSet, err = p.Diff(old)
+		if err != nil {
+			return errors.Wrap(err, "Diff()")
+		}
+
+		if len(changeSet) == 0 {
+			return httpio.NewBadRequestMessagef("No changes to apply for %s (%s)", p.Resource(), p.PrimaryKey().String())
+		}
+	}
+
+	stmt, err := p.sqlUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := p.sqlExec(ctx, tx, stmt)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return httpio.NewNotFoundMessagef("%s (%s) not found", p.Resource(), p.PrimaryKey().String())
+	}
+
+	if p.querySet.rMeta.trackChanges {
+		if err := p.sqlInsertDataChangeEvent(ctx, tx, event, changeSet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *PatchSet[Resource]) sqlInsertOrUpdate(ctx context.Context, tx sqlTx, eventSource ...string) error {
+	if tx.dbType() != p.querySet.rMeta.dbType {
+		return errors.Newf("can not apply %s with dbType %s to %s", p.Resource(), p.querySet.rMeta.dbType, tx.dbType())
+	}
+
+	event, err := p.validateEventSource(eventSource)
+	if err != nil {
+		return err
+	}
+
+	var changeSet map[accesstypes.Field]DiffElem
+	if p.querySet.rMeta.trackChanges {
+		old, found, err := p.sqlReadForUpdate(ctx, tx, p.querySet)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			changeSet, err = p.Diff(old)
+			if err != nil {
+				return errors.Wrap(err, "Diff()")
+			}
+		} else {
+			changeSet, err = p.insertChangeSet()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	stmt, err := p.sqlInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	keyColumns, err := p.sqlKeyColumns()
+	if err != nil {
+		return err
+	}
+
+	updates := make([]string, 0, p.Len())
+	for _, field := range p.Fields() {
+		c, ok := p.querySet.rMeta.fieldMap[field]
+		if !ok {
+			return errors.Newf("field %s not found in struct", field)
+		}
+		if slices.Contains(keyColumns, c.tag) {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf(`"%s" = EXCLUDED."%s"`, c.tag, c.tag))
+	}
+	slices.Sort(updates)
+
+	conflict := fmt.Sprintf(` ON CONFLICT ("%s")`, strings.Join(keyColumns, `", "`))
+	if len(updates) == 0 {
+		stmt.Sql += conflict + " DO NOTHING"
+	} else {
+		stmt.Sql += conflict + " DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	if _, err := p.sqlExec(ctx, tx, stmt); err != nil {
+		return err
+	}
+
+	if p.querySet.rMeta.trackChanges {
+		if err := p.sqlInsertDataChangeEvent(ctx, tx, event, changeSet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *PatchSet[Resource]) sqlDelete(ctx context.Context, tx sqlTx, eventSource ...string) error {
+	event, err := p.validateEventSource(eventSource)
+	if err != nil {
+		return err
+	}
+
+	var changeSet map[accesstypes.Field]DiffElem
+	if p.querySet.rMeta.trackChanges {
+		old, found, err := p.sqlReadForUpdate(ctx, tx, p.deleteQuerySet())
+		if err != nil {
+			return err
+		}
+		if !found {
+			return httpio.NewNotFoundMessagef("%s (%s) not found", p.Resource(), p.PrimaryKey().String())
+		}
+
+		changeSet, err = p.deleteChangeSet(old)
+		if err != nil {
+			return errors.Wrap(err, "Diff()")
+		}
+	}
+
+	where, err := p.querySet.Where()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.Where()")
+	}
+	if where.Sql == "" {
+		return errors.New("PatchSet must include at least one primary key to delete")
+	}
+
+	table, err := p.querySet.table()
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := p.sqlExec(ctx, tx, Statement{
+		Sql:    fmt.Sprintf("DELETE FROM %s %s", table, where.Sql),
+		Params: where.Params,
+	})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return httpio.NewNotFoundMessagef("%s (%s) not found", p.Resource(), p.PrimaryKey().String())
+	}
+
+	if p.querySet.rMeta.trackChanges {
+		if err := p.sqlInsertDataChangeEvent(ctx, tx, event, changeSet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sqlReadForUpdate reads the current row selected by qSet. On Postgres the row is locked for the
+// rest of the transaction. SQLite has no row locks; its write transactions lock the whole database.
+func (p *PatchSet[Resource]) sqlReadForUpdate(ctx context.Context, tx sqlTx, qSet *QuerySet[Resource]) (old *Resource, found bool, err error) {
+	stmt, err := qSet.sqlStmt()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "QuerySet.sqlStmt()")
+	}
+	if tx.dbType() == PostgresDBType {
+		stmt.Sql += " FOR UPDATE"
+	}
+
+	old = new(Resource)
+	found, err = tx.get(ctx, stmt, old)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return old, found, nil
+}
+
+func (p *PatchSet[Resource]) sqlInsertStmt() (Statement, error) {
+	patch, err := p.Resolve()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "Resolve()")
+	}
+
+	columns := make([]string, 0, len(patch))
+	for column := range patch {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+
+	table, err := p.querySet.table()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	values := make([]string, 0, len(columns))
+	params := make(map[string]any, len(columns))
+	for i, column := range columns {
+		param := fmt.Sprintf("value%d", i)
+		values = append(values, "@"+param)
+		params[param] = patch[column]
+	}
+
+	return Statement{
+		Sql:    fmt.Sprintf(`INSERT INTO %s ("%s") VALUES (%s)`, table, strings.Join(columns, `", "`), strings.Join(values, ", ")),
+		Params: params,
+	}, nil
+}
+
+func (p *PatchSet[Resource]) sqlUpdateStmt() (Statement, error) {
+	where, err := p.querySet.Where()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.Where()")
+	}
+	if where.Sql == "" {
+		return Statement{}, errors.New("PatchSet must include at least one primary key to update")
+	}
+
+	patch := make(map[string]any, p.Len())
+	for field, value := range p.Data() {
+		c, ok := p.querySet.rMeta.fieldMap[field]
+		if !ok {
+			return Statement{}, errors.Newf("field %s not found in struct", field)
+		}
+		patch[c.tag] = value
+	}
+	if len(patch) == 0 {
+		return Statement{}, httpio.NewBadRequestMessagef("No changes to apply for %s (%s)", p.Resource(), p.PrimaryKey().String())
+	}
+
+	columns := make([]string, 0, len(patch))
+	for column := range patch {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+
+	table, err := p.querySet.table()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	sets := make([]string, 0, len(columns))
+	params := make(map[string]any, len(columns)+len(where.Params))
+	for i, column := range columns {
+		param := fmt.Sprintf("set%d", i)
+		sets = append(sets, fmt.Sprintf(`"%s" = @%s`, column, param))
+		params[param] = patch[column]
+	}
+
+	for param, value := range where.Params {
+		params[param] = value
+	}
+
+	return Statement{
+		Sql:    fmt.Sprintf("UPDATE %s SET %s %s", table, strings.Join(sets, ", "), where.Sql),
+		Params: params,
+	}, nil
+}
+
+func (p *PatchSet[Resource]) sqlKeyColumns() ([]string, error) {
+	parts := p.PrimaryKey().Parts()
+	columns := make([]string, 0, len(parts))
+	for _, part := range parts {
+		c, ok := p.querySet.rMeta.fieldMap[part.Key]
+		if !ok {
+			return nil, errors.Newf("field %s not found in struct", part.Key)
+		}
+		columns = append(columns, c.tag)
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("PatchSet must include at least one primary key")
+	}
+
+	return columns, nil
+}
+
+// sqlInsertDataChangeEvent records changeSet in the change tracking table. EventTime is the
+// transaction timestamp, the closest equivalent of the Spanner commit timestamp.
+func (p *PatchSet[Resource]) sqlInsertDataChangeEvent(ctx context.Context, tx sqlTx, eventSource string, changeSet map[accesstypes.Field]DiffElem) error {
+	changeSetJSON, err := json.Marshal(changeSet)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal()")
+	}
+
+	table, err := quoteIdent(p.querySet.rMeta.dbType, p.querySet.rMeta.changeTrackingTable)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.sqlExec(ctx, tx, Statement{
+		Sql: fmt.Sprintf(`INSERT INTO %s ("TableName", "RowId", "EventTime", "EventSource", "ChangeSet") VALUES (@tablename, @rowid, CURRENT_TIMESTAMP, @eventsource, @changeset)`, table),
+		Params: map[string]any{
+			"tablename":   string(p.Resource()),
+			"rowid":       p.PrimaryKey().RowID(),
+			"eventsource": eventSource,
+			"changeset":   string(changeSetJSON),
+		},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *PatchSet[Resource]) sqlExec(ctx context.Context, tx sqlTx, stmt Statement) (int64, error) {
+	rowsAffected, err := tx.exec(ctx, stmt)
+	if err != nil {
+		kind, name, ok := tx.constraint(err)
+		if !ok {
+			return 0, err
+		}
+
+		return 0, p.constraintError(err, kind, name)
+	}
+
+	return rowsAffected, nil
+}
+
+// constraintError maps integrity constraint violations to client messages.
+func (p *PatchSet[Resource]) constraintError(err error, kind constraintKind, name string) error {
+	switch kind {
+	case uniqueViolation:
+		return httpio.NewConflictMessageWithErrorf(err, "%s (%s) already exists", p.Resource(), p.PrimaryKey().String())
+	case foreignKeyViolation:
+		if p.patchType == DeletePatchType {
+			return httpio.NewConflictMessageWithErrorf(err, "%s (%s) is still referenced by another resource", p.Resource(), p.PrimaryKey().String())
+		}
+
+		return httpio.NewBadRequestMessageWithErrorf(err, "%s references a resource that does not exist (%s)", p.Resource(), name)
+	case notNullViolation:
+		return httpio.NewBadRequestMessageWithErrorf(err, "%s requires a value for %s", p.Resource(), name)
+	case checkViolation:
+		return httpio.NewBadRequestMessageWithErrorf(err, "%s failed validation (%s)", p.Resource(), name)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,203 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type execCall struct {
+	Sql  string
+	Args []any
+}
+
+// fakeTx returns rows from fakeQuerier and records each Exec, reporting rowsAffected rows.
+type fakeTx struct {
+	fakeQuerier
+	rowsAffected string
+	execErr      error
+	execs        []execCall
+}
+
+func (f *fakeTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.execs = append(f.execs, execCall{Sql: strings.Join(strings.Fields(sql), " "), Args: args})
+	if f.execErr != nil {
+		return pgconn.CommandTag{}, f.execErr
+	}
+
+	return pgconn.NewCommandTag(f.rowsAffected), nil
+}
+
+func newPostgresPatchSet(trackChanges bool) *PatchSet[filterResource] {
+	return NewPatchSet(&ResourceMetadata[filterResource]{
+		fieldMap:            structTags(reflect.TypeFor[filterResource](), string(PostgresDBType)),
+		primaryKeys:         primaryKeyFields(reflect.TypeFor[filterResource]()),
+		dbType:              PostgresDBType,
+		changeTrackingTable: "DataChangeEvents",
+		trackChanges:        trackChanges,
+	})
+}
+
+func TestPatchSet_PostgresApply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		patchType    PatchType
+		trackChanges bool
+		rows         [][]any
+		rowsAffected string
+		execErr      error
+		wantExecs    []execCall
+		wantErr      string
+	}{
+		{
+			name:         "create",
+			patchType:    CreatePatchType,
+			rowsAffected: "INSERT 0 1",
+			wantExecs: []execCall{
+				{Sql: `INSERT INTO "FilterResources" ("id", "status") VALUES ($1, $2)`, Args: []any{"abc", "active"}},
+			},
+		},
+		{
+			name:         "update with change tracking",
+			patchType:    UpdatePatchType,
+			trackChanges: true,
+			rows:         [][]any{{"pending"}},
+			rowsAffected: "UPDATE 1",
+			wantExecs: []execCall{
+				{Sql: `UPDATE "FilterResources" SET "status" = $1 WHERE "id" = $2`, Args: []any{"active", "abc"}},
+				{
+					Sql:  `INSERT INTO "DataChangeEvents" ("TableName", "RowId", "EventTime", "EventSource", "ChangeSet") VALUES ($1, $2, CURRENT_TIMESTAMP, $3, $4)`,
+					Args: []any{"FilterResources", "abc", "test", `{"Status":{"Old":"pending","New":"active"}}`},
+				},
+			},
+		},
+		{
+			name:         "update not found",
+			patchType:    UpdatePatchType,
+			rowsAffected: "UPDATE 0",
+			wantErr:      "FilterResources (ID: abc) not found",
+		},
+		{
+			name:         "update without changes",
+			patchType:    UpdatePatchType,
+			trackChanges: true,
+			rows:         [][]any{{"active"}},
+			wantErr:      "No changes to apply for FilterResources (ID: abc)",
+		},
+		{
+			name:         "delete",
+			patchType:    DeletePatchType,
+			rowsAffected: "DELETE 1",
+			wantExecs: []execCall{
+				{Sql: `DELETE FROM "FilterResources" WHERE "id" = $1`, Args: []any{"abc"}},
+			},
+		},
+		{
+			name:      "unique violation",
+			patchType: CreatePatchType,
+			execErr:   &pgconn.PgError{Code: "23505", ConstraintName: "filterresources_pkey"},
+			wantErr:   "FilterResources (ID: abc) already exists",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := &fakeTx{
+				fakeQuerier:  fakeQuerier{columns: []string{"status"}, rows: tt.rows},
+				rowsAffected: tt.rowsAffected,
+				execErr:      tt.execErr,
+			}
+			p := newPostgresPatchSet(tt.trackChanges).SetPatchType(tt.patchType).SetKey("ID", "abc")
+			if tt.patchType != DeletePatchType {
+				p.Set("Status", "active")
+			}
+
+			var eventSource []string
+			if tt.trackChanges {
+				eventSource = []string{"test"}
+			}
+
+			err := p.PostgresApply(context.Background(), tx, eventSource...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PatchSet.PostgresApply() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("PatchSet.PostgresApply() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantExecs, tx.execs); diff != "" {
+				t.Errorf("PatchSet.PostgresApply() execs mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPatchSet_PostgresInsertOrUpdate(t *testing.T) {
+	t.Parallel()
+
+	tx := &fakeTx{rowsAffected: "INSERT 0 1"}
+	p := newPostgresPatchSet(false).SetKey("ID", "abc").Set("Status", "active").Set("Count", int64(2))
+
+	if err := p.PostgresInsertOrUpdate(context.Background(), tx); err != nil {
+		t.Fatalf("PatchSet.PostgresInsertOrUpdate() error = %v", err)
+	}
+
+	want := []execCall{{
+		Sql: `INSERT INTO "FilterResources" ("count", "id", "status") VALUES ($1, $2, $3)` +
+			` ON CONFLICT ("id") DO UPDATE SET "count" = EXCLUDED."count", "status" = EXCLUDED."status"`,
+		Args: []any{int64(2), "abc", "active"},
+	}}
+	if diff := cmp.Diff(want, tx.execs); diff != "" {
+		t.Errorf("PatchSet.PostgresInsertOrUpdate() execs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// caseResource has columns whose names only differ by case.
+type caseResource struct {
+	ID       string `postgres:"id"   query:"primaryKey"`
+	Name     string `postgres:"name"`
+	NameCase string `postgres:"Name"`
+}
+
+func (caseResource) Resource() accesstypes.Resource {
+	return "CaseResources"
+}
+
+func (caseResource) DefaultConfig() Config {
+	return Config{DBType: PostgresDBType}
+}
+
+func TestPatchSet_sqlUpdateStmt(t *testing.T) {
+	t.Parallel()
+
+	p := NewPatchSet(&ResourceMetadata[caseResource]{
+		fieldMap:    structTags(reflect.TypeFor[caseResource](), string(PostgresDBType)),
+		primaryKeys: primaryKeyFields(reflect.TypeFor[caseResource]()),
+		dbType:      PostgresDBType,
+	}).SetKey("ID", "abc").Set("Name", "lower").Set("NameCase", "upper")
+
+	got, err := p.sqlUpdateStmt()
+	if err != nil {
+		t.Fatalf("PatchSet.sqlUpdateStmt() error = %v", err)
+	}
+
+	want := Statement{
+		Sql:    `UPDATE "CaseResources" SET "Name" = @set0, "name" = @set1 WHERE "id" = @id`,
+		Params: map[string]any{"set0": "upper", "set1": "lower", "id": "abc"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PatchSet.sqlUpdateStmt() mismatch (-want +got):\n%s", diff)
+	}
+}
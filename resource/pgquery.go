@@ -0,0 +1,94 @@
+package resource
+
+import "fmt"
+
+// postgresQueryParser compiles a search string to Postgres search predicates. It accepts the
+// same query language as spannerQueryParser.
+//
+// The search key names the column searched: a tsvector column for FullText, and a text column
+// for Ngram, which requires the pg_trgm extension, and SubString.
+type postgresQueryParser struct {
+	terms searchQuery
+	// prefix namespaces the parameter names when several searches share a statement
+	prefix string
+}
+
+func parsePostgresQuery(query string) (*postgresQueryParser, error) {
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &postgresQueryParser{terms: terms}, nil
+}
+
+func (s postgresQueryParser) parseToSearchFullText(column SearchKey) *Statement {
+	params := make(map[string]any)
+	sql := s.terms.render(func(t searchTerm) string {
+		param := fmt.Sprintf("%sfulltextterm%d", s.prefix, t.index)
+		params[param] = quoteRQueryTerm(t.text)
+
+		return fmt.Sprintf(`"%s" @@ websearch_to_tsquery(@%s)`, column, param)
+	})
+
+	return &Statement{
+		Sql:    sql,
+		Params: params,
+	}
+}
+
+func (s postgresQueryParser) parseToFullTextScore(column SearchKey) *Statement {
+	param := s.prefix + "fulltextscorequery"
+
+	return &Statement{
+		Sql:    fmt.Sprintf(`ts_rank("%s", websearch_to_tsquery(@%s))`, column, param),
+		Params: map[string]any{param: s.terms.anyQuery("or")},
+	}
+}
+
+func (s postgresQueryParser) parseToSearchNgrams(column SearchKey) *Statement {
+	params := make(map[string]any)
+	sql := s.terms.render(func(t searchTerm) string {
+		param := fmt.Sprintf("%sngramsterm%d", s.prefix, t.index)
+		params[param] = t.text
+
+		return fmt.Sprintf(`"%s" %% @%s`, column, param)
+	})
+
+	return &Statement{
+		Sql:    sql,
+		Params: params,
+	}
+}
+
+func (s postgresQueryParser) parseToNgramsScore(column SearchKey) *Statement {
+	return s.terms.sum(func(t searchTerm) (string, string, any) {
+		param := fmt.Sprintf("%sngramsterm%d", s.prefix, t.index)
+
+		return fmt.Sprintf(`similarity("%s", @%s)`, column, param), param, t.text
+	})
+}
+
+func (s postgresQueryParser) parseToSearchSubstring(column SearchKey) *Statement {
+	params := make(map[string]any)
+	sql := s.terms.render(func(t searchTerm) string {
+		param := fmt.Sprintf("%ssearchsubstringterm%d", s.prefix, t.index)
+		params[param] = "%" + escapeLike(t.text) + "%"
+
+		return fmt.Sprintf(`"%s" ILIKE @%s`, column, param)
+	})
+
+	return &Statement{
+		Sql:    sql,
+		Params: params,
+	}
+}
+
+// parseToSubstringScore counts the terms which match.
+func (s postgresQueryParser) parseToSubstringScore(column SearchKey) *Statement {
+	return s.terms.sum(func(t searchTerm) (string, string, any) {
+		param := fmt.Sprintf("%ssearchsubstringterm%d", s.prefix, t.index)
+
+		return fmt.Sprintf(`(CASE WHEN "%s" ILIKE @%s THEN 1 ELSE 0 END)`, column, param), param, "%" + escapeLike(t.text) + "%"
+	})
+}
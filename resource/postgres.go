@@ -0,0 +1,181 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgresQuerier runs a query against Postgres. It is satisfied by *pgx.Conn, *pgxpool.Pool and pgx.Tx.
+type PostgresQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// PostgresTx runs statements inside a Postgres transaction. It is satisfied by pgx.Tx.
+type PostgresTx interface {
+	PostgresQuerier
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// positionalStatement rewrites the @name parameters in stmt to numbered placeholders rendered with
+// placeholder, such as "$%d" for Postgres, returning the arguments in placeholder order. Parameters used
+// more than once share a placeholder, and operators such as @@ and @> and text inside quotes are left untouched.
+func positionalStatement(stmt Statement, placeholder string) (string, []any, error) {
+	var (
+		sql       strings.Builder
+		args      []any
+		positions = make(map[string]int)
+		quote     byte
+	)
+
+	for i := 0; i < len(stmt.Sql); i++ {
+		c := stmt.Sql[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '@' && i+1 < len(stmt.Sql) && stmt.Sql[i+1] == '@':
+			sql.WriteString("@@")
+			i++
+
+			continue
+		case c == '@' && i+1 < len(stmt.Sql) && isParamStart(stmt.Sql[i+1]):
+			end := i + 1
+			for end < len(stmt.Sql) && isParamChar(stmt.Sql[end]) {
+				end++
+			}
+			name := stmt.Sql[i+1 : end]
+
+			pos, ok := positions[name]
+			if !ok {
+				value, ok := stmt.Params[name]
+				if !ok {
+					return "", nil, errors.Newf("missing value for parameter @%s", name)
+				}
+				args = append(args, value)
+				pos = len(args)
+				positions[name] = pos
+			}
+
+			fmt.Fprintf(&sql, placeholder, pos)
+			i = end - 1
+
+			continue
+		}
+
+		sql.WriteByte(c)
+	}
+
+	if quote != 0 {
+		return "", nil, errors.Newf("unterminated %c quote in statement", quote)
+	}
+
+	return sql.String(), args, nil
+}
+
+func isParamStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isParamChar(c byte) bool {
+	return isParamStart(c) || ('0' <= c && c <= '9')
+}
+
+// postgresQuery executes stmt using q.
+func postgresQuery(ctx context.Context, q PostgresQuerier, stmt Statement) (pgx.Rows, error) {
+	sql, args, err := positionalStatement(stmt, "$%d")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "PostgresQuerier.Query()")
+	}
+
+	return rows, nil
+}
+
+// postgresGet scans the first row into dst, a pointer to a struct, and reports whether a row was found.
+func postgresGet(rows pgx.Rows, dst any) (found bool, err error) {
+	defer rows.Close()
+
+	return scanGet(rows, postgresFieldNames(rows), PostgresDBType, dst)
+}
+
+// postgresSelect scans all rows into dst, a pointer to a slice of structs or struct pointers.
+func postgresSelect(rows pgx.Rows, dst any) error {
+	defer rows.Close()
+
+	return scanSelect(rows, postgresFieldNames(rows), PostgresDBType, dst)
+}
+
+func postgresFieldNames(rows pgx.Rows) []string {
+	fields := rows.FieldDescriptions()
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+// postgresTx adapts a PostgresTx for PatchSet writes.
+type postgresTx struct {
+	tx PostgresTx
+}
+
+func (postgresTx) dbType() DBType {
+	return PostgresDBType
+}
+
+func (t postgresTx) get(ctx context.Context, stmt Statement, dst any) (bool, error) {
+	rows, err := postgresQuery(ctx, t.tx, stmt)
+	if err != nil {
+		return false, err
+	}
+
+	return postgresGet(rows, dst)
+}
+
+func (t postgresTx) exec(ctx context.Context, stmt Statement) (int64, error) {
+	sql, args, err := positionalStatement(stmt, "$%d")
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := t.tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "PostgresTx.Exec()")
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (postgresTx) constraint(err error) (constraintKind, string, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return 0, "", false
+	}
+
+	switch pgErr.Code {
+	case "23505":
+		return uniqueViolation, pgErr.ConstraintName, true
+	case "23503":
+		return foreignKeyViolation, pgErr.ConstraintName, true
+	case "23502":
+		return notNullViolation, pgErr.ColumnName, true
+	case "23514":
+		return checkViolation, pgErr.ConstraintName, true
+	default:
+		return 0, "", false
+	}
+}
@@ -0,0 +1,194 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Test_positionalStatement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stmt     Statement
+		wantSql  string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{
+			name:     "params in order of use",
+			stmt:     Statement{Sql: `SELECT "id" FROM t WHERE "status" = @status AND "id" = @id`, Params: map[string]any{"id": "abc", "status": "active"}},
+			wantSql:  `SELECT "id" FROM t WHERE "status" = $1 AND "id" = $2`,
+			wantArgs: []any{"active", "abc"},
+		},
+		{
+			name:     "repeated param shares a placeholder",
+			stmt:     Statement{Sql: `WHERE "name" % @ngramsterm0 ORDER BY similarity("name", @ngramsterm0)`, Params: map[string]any{"ngramsterm0": "mik"}},
+			wantSql:  `WHERE "name" % $1 ORDER BY similarity("name", $1)`,
+			wantArgs: []any{"mik"},
+		},
+		{
+			name:     "operators and quoted text are untouched",
+			stmt:     Statement{Sql: `WHERE "tsv" @@ websearch_to_tsquery(@q) AND "tags" @> '{@x}' AND "a@b" = @search1q`, Params: map[string]any{"q": "mike", "search1q": 1}},
+			wantSql:  `WHERE "tsv" @@ websearch_to_tsquery($1) AND "tags" @> '{@x}' AND "a@b" = $2`,
+			wantArgs: []any{"mike", 1},
+		},
+		{
+			name:    "missing param",
+			stmt:    Statement{Sql: `WHERE "id" = @id`},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			stmt:    Statement{Sql: `WHERE "id = @id`, Params: map[string]any{"id": 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotSql, gotArgs, err := positionalStatement(tt.stmt, "$%d")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("positionalStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantSql, gotSql); diff != "" {
+				t.Errorf("positionalStatement() SQL mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantArgs, gotArgs); diff != "" {
+				t.Errorf("positionalStatement() args mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakeQuerier records the query it receives and returns rows of the given column values.
+type fakeQuerier struct {
+	columns []string
+	rows    [][]any
+	sql     string
+	args    []any
+}
+
+func (f *fakeQuerier) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.sql, f.args = sql, args
+
+	return &fakeRows{columns: f.columns, rows: f.rows, index: -1}, nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+	columns []string
+	rows    [][]any
+	index   int
+}
+
+func (r *fakeRows) Close()     {}
+func (r *fakeRows) Err() error { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.index++
+
+	return r.index < len(r.rows)
+}
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, 0, len(r.columns))
+	for _, c := range r.columns {
+		fields = append(fields, pgconn.FieldDescription{Name: c})
+	}
+
+	return fields
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if len(dest) != len(r.rows[r.index]) {
+		return fmt.Errorf("got %d destinations for %d values", len(dest), len(r.rows[r.index]))
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.rows[r.index][i]))
+	}
+
+	return nil
+}
+
+func TestQuerySet_PostgresRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		rows         [][]any
+		want         filterResource
+		wantNotFound bool
+	}{
+		{
+			name: "found",
+			rows: [][]any{{"abc", "active"}},
+			want: filterResource{ID: "abc", Status: "active"},
+		},
+		{
+			name:         "not found",
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &fakeQuerier{columns: []string{"id", "status"}, rows: tt.rows}
+			q := newFilterQuerySet(PostgresDBType).AddField("ID").AddField("Status")
+			q.SetKey("ID", "abc")
+
+			var got filterResource
+			err := q.PostgresRead(context.Background(), db, &got)
+			if tt.wantNotFound {
+				if err == nil || !strings.Contains(err.Error(), "FilterResources (ID: abc) not found") {
+					t.Fatalf("QuerySet.PostgresRead() error = %v, want not found", err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("QuerySet.PostgresRead() error = %v", err)
+			}
+
+			if diff := cmp.Diff(`SELECT "id", "status" FROM "FilterResources" WHERE "id" = $1`, strings.Join(strings.Fields(db.sql), " ")); diff != "" {
+				t.Errorf("QuerySet.PostgresRead() SQL mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]any{"abc"}, db.args); diff != "" {
+				t.Errorf("QuerySet.PostgresRead() args mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.PostgresRead() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_PostgresList(t *testing.T) {
+	t.Parallel()
+
+	db := &fakeQuerier{columns: []string{"id", "status"}, rows: [][]any{{"a", "active"}, {"b", "pending"}}}
+	q := newFilterQuerySet(PostgresDBType).AddField("ID").AddField("Status")
+	q.AddFilter(Filter{Field: "Status", Operator: InOperator, Value: []string{"active", "pending"}})
+
+	var got []*filterResource
+	if err := q.PostgresList(context.Background(), db, &got); err != nil {
+		t.Fatalf("QuerySet.PostgresList() error = %v", err)
+	}
+
+	want := []*filterResource{{ID: "a", Status: "active"}, {ID: "b", Status: "pending"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("QuerySet.PostgresList() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(`SELECT "id", "status" FROM "FilterResources" WHERE "status" = ANY($1)`, strings.Join(strings.Fields(db.sql), " ")); diff != "" {
+		t.Errorf("QuerySet.PostgresList() SQL mismatch (-want +got):\n%s", diff)
+	}
+}
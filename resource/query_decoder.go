@@ -4,8 +4,10 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"reflect"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/cccteam/ccc/accesstypes"
 	"github.com/cccteam/httpio"
@@ -21,6 +23,12 @@ type (
 type QueryDecoder[Resource Resourcer, Request any] struct {
 	fieldMapper       *FieldMapper
 	searchKeys        *SearchKeys
+	sortable          map[accesstypes.Field]struct{}
+	timestampBounds   bool
+	forceIndexes      map[accesstypes.Field]string
+	references        map[accesstypes.Field]Reference
+	referenced        map[accesstypes.Resource]ReferencedResource
+	includes          []decoderInclude
 	resourceSet       *ResourceSet[Resource, Request]
 	permissionChecker accesstypes.Enforcer
 	domainFromCtx     DomainFromCtx
@@ -36,9 +44,21 @@ func NewQueryDecoder[Res Resourcer, Req any](resSet *ResourceSet[Res, Req], perm
 		return nil, errors.Wrap(err, "NewFieldMapper()")
 	}
 
+	references, err := referencesFromTags(reflect.TypeFor[Req]())
+	if err != nil {
+		return nil, errors.Wrap(err, "referencesFromTags()")
+	}
+
+	searchKeys, err := ParseSearchKeys[Req](res)
+	if err != nil {
+		return nil, errors.Wrap(err, "ParseSearchKeys()")
+	}
+
 	return &QueryDecoder[Res, Req]{
 		fieldMapper:       mapper,
-		searchKeys:        NewSearchKeys[Req](res),
+		searchKeys:        searchKeys,
+		forceIndexes:      forceIndexFields[Req](),
+		references:        references,
 		resourceSet:       resSet,
 		permissionChecker: permChecker,
 		domainFromCtx:     domainFromCtx,
@@ -46,6 +66,77 @@ func NewQueryDecoder[Res Resourcer, Req any](resSet *ResourceSet[Res, Req], perm
 	}, nil
 }
 
+// RestrictSortToIndexedFields only allows sorting by the request fields tagged index:"true",
+// so that clients can not request sorts which require a full scan. By default every field may be sorted.
+func (d *QueryDecoder[Resource, Request]) RestrictSortToIndexedFields() *QueryDecoder[Resource, Request] {
+	d.sortable = sortableFields[Request]()
+
+	return d
+}
+
+// AllowTimestampBound accepts the asOf and maxStaleness query parameters, which set the timestamp bound of the
+// decoded query. The handler must read the query with a transaction returned by SpannerReadTransaction, since the
+// bound can not be applied to an existing transaction. Without it, requests with these parameters are rejected.
+func (d *QueryDecoder[Resource, Request]) AllowTimestampBound() *QueryDecoder[Resource, Request] {
+	d.timestampBounds = true
+
+	return d
+}
+
+// ReferencedResource resolves the field permissions of the columns of a resource which can be expanded.
+// It is implemented by *ResourceSet.
+type ReferencedResource interface {
+	BaseResource() accesstypes.Resource
+	columnResource(column string, perm accesstypes.Permission) (res accesstypes.Resource, required bool, err error)
+}
+
+// AddReferencedResource allows the text and embedded columns of referenced, named in references struct tags,
+// to be expanded. Columns which require a permission the user does not have are left out of the expansion.
+func (d *QueryDecoder[Resource, Request]) AddReferencedResource(referenced ReferencedResource) *QueryDecoder[Resource, Request] {
+	if d.referenced == nil {
+		d.referenced = make(map[accesstypes.Resource]ReferencedResource)
+	}
+	d.referenced[referenced.BaseResource()] = referenced
+
+	return d
+}
+
+// Includer decodes the query set of a child resource for Include. It is implemented by *QueryDecoder.
+type Includer interface {
+	decodeInclude(ctx context.Context) (IncludeQuerier, error)
+}
+
+// decoderInclude is a child collection which can be requested with the include query parameter.
+type decoderInclude struct {
+	name        string
+	child       Includer
+	foreignKeys []accesstypes.Field
+}
+
+// AddInclude allows the child collection decoded by child to be requested with the include query parameter,
+// for example ?include=lines or ?include=lines:10 to read at most 10 lines for each parent. The child fields
+// are limited to those the user has permission to view, as for the parent. foreignKeys are the child fields
+// holding the parent's primary key, and may be omitted for a child interleaved in the parent.
+func (d *QueryDecoder[Resource, Request]) AddInclude(name string, child Includer, foreignKeys ...accesstypes.Field) *QueryDecoder[Resource, Request] {
+	d.includes = append(d.includes, decoderInclude{name: name, child: child, foreignKeys: foreignKeys})
+
+	return d
+}
+
+func (d *QueryDecoder[Resource, Request]) decodeInclude(ctx context.Context) (IncludeQuerier, error) {
+	fields, err := d.fields(ctx, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	qSet := NewQuerySet(d.resourceSet.ResourceMetadata())
+	for _, field := range fields {
+		qSet.AddField(field)
+	}
+
+	return qSet, nil
+}
+
 func (d *QueryDecoder[Resource, Request]) Decode(request *http.Request) (*QuerySet[Resource], error) {
 	fields, err := d.fields(request.Context(), request.URL.Query())
 	if err != nil {
@@ -57,17 +148,187 @@ func (d *QueryDecoder[Resource, Request]) Decode(request *http.Request) (*QueryS
 		qSet.AddField(field)
 	}
 
-	set, err := parseSearchParam(d.searchKeys, request.URL.Query())
+	filters, err := parseFilterParam(d.fieldMapper, reflect.TypeFor[Resource](), d.resourceSet.ResourceMetadata().fieldMap, request.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range filters {
+		if ok, err := d.fieldPermitted(request.Context(), filter.Field); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, httpio.NewForbiddenMessagef("%s permission on %s is required to filter by it", d.resourceSet.Permission(), d.resourceSet.Resource(filter.Field))
+		}
+		qSet.AddFilter(filter)
+	}
+
+	sortFields, err := parseSortParam(d.fieldMapper, d.sortable, d.resourceSet.ResourceMetadata().fieldMap, request.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	for _, sortField := range sortFields {
+		if ok, err := d.fieldPermitted(request.Context(), sortField.Field); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, httpio.NewForbiddenMessagef("%s permission on %s is required to sort by it", d.resourceSet.Permission(), d.resourceSet.Resource(sortField.Field))
+		}
+	}
+	qSet.OrderBy(sortFields...)
+
+	aggregates, groupBy, err := parseAggregateParams(d.fieldMapper, reflect.TypeFor[Resource](), d.resourceSet.ResourceMetadata().fieldMap, request.URL.Query())
 	if err != nil {
 		return nil, err
 	}
-	if set != nil {
-		qSet.SetSearchParam(set)
+	for _, aggregate := range aggregates {
+		if aggregate.Field != "" {
+			if ok, err := d.fieldPermitted(request.Context(), aggregate.Field); err != nil {
+				return nil, err
+			} else if !ok {
+				return nil, httpio.NewForbiddenMessagef("%s permission on %s is required to aggregate it", d.resourceSet.Permission(), d.resourceSet.Resource(aggregate.Field))
+			}
+		}
+		qSet.AddAggregate(aggregate)
+	}
+	for _, field := range groupBy {
+		if ok, err := d.fieldPermitted(request.Context(), field); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, httpio.NewForbiddenMessagef("%s permission on %s is required to group by it", d.resourceSet.Permission(), d.resourceSet.Resource(field))
+		}
+	}
+	qSet.GroupBy(groupBy...).SetFieldMapper(d.fieldMapper)
+	if len(aggregates) > 0 || len(groupBy) > 0 {
+		if _, err := qSet.aggregateOrder(); err != nil {
+			return nil, err
+		}
+	}
+
+	expansions, err := parseExpandParam(d.fieldMapper, d.references, request.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	for _, expansion := range expansions {
+		if ok, err := d.fieldPermitted(request.Context(), expansion.Field); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, httpio.NewForbiddenMessagef("%s permission on %s is required to expand it", d.resourceSet.Permission(), d.resourceSet.Resource(expansion.Field))
+		}
+
+		ctx := request.Context()
+		if ok, _, subject, err := requireResources(ctx, d.permissionChecker, d.userFromCtx(ctx), d.domainFromCtx(ctx), d.resourceSet.Permission(), expansion.Reference.Resource); err != nil {
+			return nil, errors.Wrap(err, "requireResources()")
+		} else if !ok {
+			return nil, httpio.NewForbiddenMessagef("%s does not have %s permission on %s", subject, d.resourceSet.Permission(), expansion.Reference.Resource)
+		}
+
+		ref, err := d.permittedReference(ctx, expansion.Reference)
+		if err != nil {
+			return nil, err
+		}
+		qSet.Expand(expansion.Field, ref)
+	}
+
+	names := make([]string, 0, len(d.includes))
+	for _, include := range d.includes {
+		names = append(names, include.name)
+	}
+	includes, err := parseIncludeParam(names, request.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	if len(includes) > 0 {
+		for _, key := range qSet.rMeta.primaryKeys {
+			if ok, err := d.fieldPermitted(request.Context(), key); err != nil {
+				return nil, err
+			} else if !ok {
+				return nil, httpio.NewForbiddenMessagef("%s permission on %s is required to include child resources", d.resourceSet.Permission(), d.resourceSet.Resource(key))
+			}
+		}
+	}
+	for _, param := range includes {
+		include := d.includes[slices.IndexFunc(d.includes, func(i decoderInclude) bool { return i.name == param.name })]
+
+		child, err := include.child.decodeInclude(request.Context())
+		if err != nil {
+			return nil, err
+		}
+		qSet.Include(Include{Name: include.name, Query: child, ForeignKeys: include.foreignKeys, Limit: param.limit})
+	}
+
+	limit, offset, cursor, total, err := parsePageParams(request.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	qSet.SetLimit(limit).SetOffset(offset).SetCursor(cursor).SetIncludeTotal(total)
+	if cursor != "" {
+		if _, err := qSet.pageWhere(); err != nil {
+			return nil, err
+		}
+	}
+
+	bound, err := parseTimestampBoundParams(request.URL.Query(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if bound != nil {
+		if !d.timestampBounds {
+			return nil, httpio.NewBadRequestMessagef("asOf and maxStaleness are not supported for %s", d.resourceSet.BaseResource())
+		}
+
+		if dbType := d.resourceSet.ResourceMetadata().dbType; dbType != SpannerDBType {
+			return nil, httpio.NewBadRequestMessagef("asOf and maxStaleness are not supported with dbType %s", dbType)
+		}
+
+		if bound.maxStaleness > 0 {
+			qSet.SetMaxStaleness(bound.maxStaleness)
+		} else {
+			qSet.SetReadTimestamp(bound.asOf)
+		}
+
+		if err := qSet.checkTimestampBound(); err != nil {
+			return nil, err
+		}
+	}
+
+	sets, err := parseSearchParam(d.searchKeys, request.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		qSet.AddSearchParam(set)
+	}
+
+	if index, ok := d.forceIndex(qSet); ok {
+		qSet.UseIndex(index)
 	}
 
 	return qSet, nil
 }
 
+// forceIndex returns the index to force for a Spanner query which is not a search, from the forceIndex
+// struct tag of the first field filtered by equality, or else of the first sort field.
+func (d *QueryDecoder[Resource, Request]) forceIndex(qSet *QuerySet[Resource]) (SpannerIndex, bool) {
+	if d.resourceSet.ResourceMetadata().dbType != SpannerDBType || len(qSet.searches) > 0 {
+		return SpannerIndex{}, false
+	}
+
+	for _, filter := range qSet.Filters() {
+		if filter.Operator != EqualOperator && filter.Operator != InOperator {
+			continue
+		}
+		if name, ok := d.forceIndexes[filter.Field]; ok {
+			return SpannerIndex{Name: name, Fields: []accesstypes.Field{filter.Field}}, true
+		}
+	}
+
+	if sort := qSet.Sort(); len(sort) > 0 {
+		if name, ok := d.forceIndexes[sort[0].Field]; ok {
+			return SpannerIndex{Name: name, Fields: []accesstypes.Field{sort[0].Field}}, true
+		}
+	}
+
+	return SpannerIndex{}, false
+}
+
 func (d *QueryDecoder[Resource, Request]) fields(ctx context.Context, queryParams url.Values) ([]accesstypes.Field, error) {
 	domain, user := d.domainFromCtx(ctx), d.userFromCtx(ctx)
 
@@ -82,10 +343,11 @@ func (d *QueryDecoder[Resource, Request]) fields(ctx context.Context, queryParam
 		}
 	}
 
-	if ok, _, err := d.permissionChecker.RequireResources(ctx, user, domain, d.resourceSet.Permission(), d.resourceSet.BaseResource()); err != nil {
-		return nil, errors.Wrap(err, "accesstypes.Enforcer.RequireResources()")
+	ok, _, subject, err := requireResources(ctx, d.permissionChecker, user, domain, d.resourceSet.Permission(), d.resourceSet.BaseResource())
+	if err != nil {
+		return nil, errors.Wrap(err, "requireResources()")
 	} else if !ok {
-		return nil, httpio.NewForbiddenMessagef("user %s does not have %s permission on %s", user, d.resourceSet.Permission(), d.resourceSet.BaseResource())
+		return nil, httpio.NewForbiddenMessagef("%s does not have %s permission on %s", subject, d.resourceSet.Permission(), d.resourceSet.BaseResource())
 	}
 
 	fields := make([]accesstypes.Field, 0, d.fieldMapper.Len())
@@ -96,52 +358,134 @@ func (d *QueryDecoder[Resource, Request]) fields(ctx context.Context, queryParam
 			}
 		}
 
-		if !d.resourceSet.PermissionRequired(field, d.resourceSet.Permission()) {
+		if hasPerm, err := d.fieldPermitted(ctx, field); err != nil {
+			return nil, err
+		} else if hasPerm {
 			fields = append(fields, field)
-		} else {
-			if hasPerm, _, err := d.permissionChecker.RequireResources(ctx, user, domain, d.resourceSet.Permission(), d.resourceSet.Resource(field)); err != nil {
-				return nil, errors.Wrap(err, "hasPermission()")
-			} else if hasPerm {
-				fields = append(fields, field)
-			}
 		}
 	}
 
 	if len(fields) == 0 {
-		return nil, httpio.NewForbiddenMessagef("user %s does not have %s permission on any fields in %s", user, d.resourceSet.Permission(), d.resourceSet.BaseResource())
+		return nil, httpio.NewForbiddenMessagef("%s does not have %s permission on any fields in %s", subject, d.resourceSet.Permission(), d.resourceSet.BaseResource())
 	}
 
 	return fields, nil
 }
 
-func parseSearchParam(searchKeys *SearchKeys, queryParams url.Values) (searchSet *SearchSet, err error) {
+// fieldPermitted reports whether the user in ctx may view field.
+func (d *QueryDecoder[Resource, Request]) fieldPermitted(ctx context.Context, field accesstypes.Field) (bool, error) {
+	if !d.resourceSet.PermissionRequired(field, d.resourceSet.Permission()) {
+		return true, nil
+	}
+
+	hasPerm, _, _, err := requireResources(ctx, d.permissionChecker, d.userFromCtx(ctx), d.domainFromCtx(ctx), d.resourceSet.Permission(), d.resourceSet.Resource(field))
+	if err != nil {
+		return false, errors.Wrap(err, "requireResources()")
+	}
+
+	return hasPerm, nil
+}
+
+// permittedReference returns ref without the text and embedded columns which the user does not have
+// permission to view on the referenced resource.
+func (d *QueryDecoder[Resource, Request]) permittedReference(ctx context.Context, ref Reference) (Reference, error) {
+	if ref.Text == "" && len(ref.Embed) == 0 {
+		return ref, nil
+	}
+
+	referenced, ok := d.referenced[ref.Resource]
+	if !ok {
+		return Reference{}, errors.Newf("%s must be added with AddReferencedResource() to expand its text and embedded columns", ref.Resource)
+	}
+
+	permitted := func(column string) (bool, error) {
+		res, required, err := referenced.columnResource(column, d.resourceSet.Permission())
+		if err != nil {
+			return false, err
+		}
+		if !required {
+			return true, nil
+		}
+
+		hasPerm, _, _, err := requireResources(ctx, d.permissionChecker, d.userFromCtx(ctx), d.domainFromCtx(ctx), d.resourceSet.Permission(), res)
+		if err != nil {
+			return false, errors.Wrap(err, "requireResources()")
+		}
+
+		return hasPerm, nil
+	}
+
+	if ref.Text != "" {
+		ok, err := permitted(ref.Text)
+		if err != nil {
+			return Reference{}, err
+		}
+		if !ok {
+			ref.Text = ""
+		}
+	}
+
+	var embed []string
+	for _, column := range ref.Embed {
+		ok, err := permitted(column)
+		if err != nil {
+			return Reference{}, err
+		}
+		if ok {
+			embed = append(embed, column)
+		}
+	}
+	ref.Embed = embed
+
+	return ref, nil
+}
+
+// validateSearchQuery parses query with the search query parser of dbType.
+func validateSearchQuery(dbType DBType, query string) error {
+	var err error
+	switch dbType {
+	case SpannerDBType:
+		_, err = parseSpannerQuery(query)
+	case PostgresDBType:
+		_, err = parsePostgresQuery(query)
+	case SQLiteDBType:
+		_, err = parseSQLiteQuery(query)
+	default:
+		return errors.Newf("search is not supported with dbType %s", dbType)
+	}
+
+	return err
+}
+
+// parseSearchParam returns a SearchSet for each search key present in the query parameters, ordered by key.
+func parseSearchParam(searchKeys *SearchKeys, queryParams url.Values) (searchSets []*SearchSet, err error) {
 	if searchKeys == nil || len(queryParams) == 0 {
 		return nil, nil
 	}
 
-	var key SearchKey
+	keys := make([]SearchKey, 0, len(searchKeys.keys))
 	for searchKey := range searchKeys.keys {
 		if len(queryParams[string(searchKey)]) == 0 {
 			continue
 		}
 
-		if key != "" {
-			return nil, errors.New("only one search key is allowed")
-		}
-
 		if len(queryParams[string(searchKey)]) > 1 {
-			return nil, errors.New("only one search parameter is allowed")
+			return nil, httpio.NewBadRequestMessagef("only one %s search parameter is allowed", searchKey)
 		}
 
-		key = searchKey
+		keys = append(keys, searchKey)
 	}
+	slices.Sort(keys)
 
-	if key == "" {
-		return nil, nil
-	}
+	for _, key := range keys {
+		typ := searchKeys.keys[key]
+		val := queryParams.Get(string(key))
+		if err := validateSearchQuery(searchKeys.dbType, val); err != nil {
+			return nil, err
+		}
 
-	typ := searchKeys.keys[key]
-	val := queryParams.Get(string(key))
+		searchSets = append(searchSets, NewSearchSet(typ, key, val).SetSourceFields(searchKeys.sources[key]...).SetWeight(searchKeys.weight(key)))
+	}
 
-	return NewSearchSet(typ, key, val), nil
+	return searchSets, nil
 }
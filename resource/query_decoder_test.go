@@ -106,3 +106,190 @@ func TestQueryDecoder_fields(t *testing.T) {
 		})
 	}
 }
+
+type userResource struct {
+	ID    string `spanner:"Id"`
+	Name  string `spanner:"Name"`
+	Email string `spanner:"Email"`
+}
+
+func (userResource) Resource() accesstypes.Resource {
+	return "Users"
+}
+
+func (userResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+type userRequest struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email" perm:"Read"`
+}
+
+func TestQueryDecoder_permittedReference(t *testing.T) {
+	t.Parallel()
+
+	users := ccc.Must(NewResourceSet[userResource, userRequest](accesstypes.Read))
+
+	tests := []struct {
+		name       string
+		ref        Reference
+		referenced ReferencedResource
+		hasPerm    bool
+		want       Reference
+		wantErr    bool
+	}{
+		{
+			name: "link only",
+			ref:  Reference{Resource: "Users", Column: "Id"},
+			want: Reference{Resource: "Users", Column: "Id"},
+		},
+		{
+			name:       "permitted",
+			ref:        Reference{Resource: "Users", Column: "Id", Text: "Email", Embed: []string{"Name", "Email"}},
+			referenced: users,
+			hasPerm:    true,
+			want:       Reference{Resource: "Users", Column: "Id", Text: "Email", Embed: []string{"Name", "Email"}},
+		},
+		{
+			name:       "restricted columns dropped",
+			ref:        Reference{Resource: "Users", Column: "Id", Text: "Email", Embed: []string{"Name", "Email"}},
+			referenced: users,
+			want:       Reference{Resource: "Users", Column: "Id", Embed: []string{"Name"}},
+		},
+		{
+			name:    "referenced resource not added",
+			ref:     Reference{Resource: "Users", Column: "Id", Text: "Name"},
+			wantErr: true,
+		},
+		{
+			name:       "unknown column",
+			ref:        Reference{Resource: "Users", Column: "Id", Embed: []string{"Phone"}},
+			referenced: users,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			enforcer := mock_accesstypes.NewMockEnforcer(ctrl)
+			enforcer.EXPECT().RequireResources(gomock.Any(), gomock.Any(), gomock.Any(), accesstypes.Read, accesstypes.Resource("Users.email")).Return(tt.hasPerm, nil, nil).AnyTimes()
+
+			d, err := NewQueryDecoder(ccc.Must(NewResourceSet[testResource, testRequest](accesstypes.Read)), enforcer, func(context.Context) accesstypes.Domain { return "" }, func(context.Context) accesstypes.User { return "" })
+			if err != nil {
+				t.Fatalf("NewQueryDecoder() error = %v", err)
+			}
+			if tt.referenced != nil {
+				d.AddReferencedResource(tt.referenced)
+			}
+
+			got, err := d.permittedReference(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryDecoder.permittedReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QueryDecoder.permittedReference() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type searchRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name" fulltext:"NameTokens:2" ngram:"NameNgrams"`
+}
+
+func Test_parseSearchParam(t *testing.T) {
+	t.Parallel()
+
+	searchKeys, err := ParseSearchKeys[searchRequest](searchResource{})
+	if err != nil {
+		t.Fatalf("ParseSearchKeys() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []*SearchSet
+		wantErr bool
+	}{
+		{
+			name:  "no search",
+			query: url.Values{"columns": {"name"}},
+		},
+		{
+			name:  "multiple keys ordered by key with weights",
+			query: url.Values{"NameTokens": {"mike"}, "NameNgrams": {"mik"}},
+			want: []*SearchSet{
+				NewSearchSet(Ngram, "NameNgrams", "mik").SetSourceFields("Name"),
+				NewSearchSet(FullText, "NameTokens", "mike").SetSourceFields("Name").SetWeight(2),
+			},
+		},
+		{
+			name:    "repeated key",
+			query:   url.Values{"NameTokens": {"mike", "john"}},
+			wantErr: true,
+		},
+		{
+			name:    "malformed query",
+			query:   url.Values{"NameTokens": {`"mike`}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSearchParam(searchKeys, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSearchParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(SearchSet{})); diff != "" {
+				t.Errorf("parseSearchParam() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSearchKeys(t *testing.T) {
+	t.Parallel()
+
+	type malformedWeight struct {
+		Name string `json:"name" fulltext:"NameTokens:heavy"`
+	}
+	type zeroWeight struct {
+		Name string `json:"name" fulltext:"NameTokens:0"`
+	}
+	type sameWeight struct {
+		Name        string `json:"name"        fulltext:"NameTokens:2"`
+		Description string `json:"description" fulltext:"NameTokens:2"`
+	}
+	type conflictingWeight struct {
+		Name        string `json:"name"        fulltext:"NameTokens:2"`
+		Description string `json:"description" fulltext:"NameTokens"`
+	}
+
+	tests := []struct {
+		name    string
+		keys    func() (*SearchKeys, error)
+		wantErr bool
+	}{
+		{name: "weighted", keys: func() (*SearchKeys, error) { return ParseSearchKeys[searchRequest](searchResource{}) }},
+		{name: "same weight on two fields", keys: func() (*SearchKeys, error) { return ParseSearchKeys[sameWeight](searchResource{}) }},
+		{name: "malformed weight", keys: func() (*SearchKeys, error) { return ParseSearchKeys[malformedWeight](searchResource{}) }, wantErr: true},
+		{name: "zero weight", keys: func() (*SearchKeys, error) { return ParseSearchKeys[zeroWeight](searchResource{}) }, wantErr: true},
+		{name: "conflicting weights", keys: func() (*SearchKeys, error) { return ParseSearchKeys[conflictingWeight](searchResource{}) }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := tt.keys(); (err != nil) != tt.wantErr {
+				t.Errorf("ParseSearchKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"cloud.google.com/go/spanner"
@@ -16,10 +17,26 @@ import (
 )
 
 type QuerySet[Resource Resourcer] struct {
-	keys   *fieldSet
-	search *SearchSet
-	fields []accesstypes.Field
-	rMeta  *ResourceMetadata[Resource]
+	keys           *fieldSet
+	searches       []*SearchSet
+	filters        []Filter
+	sort           []SortField
+	limit          int
+	offset         int
+	cursor         Cursor
+	total          bool
+	snippets       bool
+	aggregates     []Aggregate
+	groupBy        []accesstypes.Field
+	keySets        []KeySet
+	index          SpannerIndex
+	timestampBound *spanner.TimestampBound
+	maxStaleness   bool
+	expansions     []Expansion
+	includes       []Include
+	fields         []accesstypes.Field
+	fieldMapper    *FieldMapper
+	rMeta          *ResourceMetadata[Resource]
 }
 
 func NewQuerySet[Resource Resourcer](rMeta *ResourceMetadata[Resource]) *QuerySet[Resource] {
@@ -35,6 +52,11 @@ func (q *QuerySet[Resource]) Resource() accesstypes.Resource {
 	return r.Resource()
 }
 
+// table returns the table name of Resource for the dbType.
+func (q *QuerySet[Resource]) table() (string, error) {
+	return quoteIdent(q.rMeta.dbType, string(q.Resource()))
+}
+
 func (q *QuerySet[Resource]) AddField(field accesstypes.Field) *QuerySet[Resource] {
 	if !slices.Contains(q.fields, field) {
 		q.fields = append(q.fields, field)
@@ -55,6 +77,45 @@ func (q *QuerySet[Resource]) Key(field accesstypes.Field) any {
 	return q.keys.Get(field)
 }
 
+// AddFilter adds a condition that rows must satisfy. Filters are combined with AND.
+func (q *QuerySet[Resource]) AddFilter(filter Filter) *QuerySet[Resource] {
+	q.filters = append(q.filters, filter)
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Filters() []Filter {
+	return q.filters
+}
+
+// OrderBy sets the ordering of the query results. In search mode results are ordered by
+// relevance first and the sort fields break ties.
+func (q *QuerySet[Resource]) OrderBy(sortFields ...SortField) *QuerySet[Resource] {
+	q.sort = sortFields
+
+	return q
+}
+
+func (q *QuerySet[Resource]) Sort() []SortField {
+	return q.sort
+}
+
+// OrderByClause returns the ORDER BY clause for the sort fields, or an empty string if none are set.
+// When the results are paginated, the primary key fields are appended so the ordering is stable.
+func (q *QuerySet[Resource]) OrderByClause() (string, error) {
+	order := q.order()
+	if len(order) == 0 {
+		return "", nil
+	}
+
+	terms, err := orderBy(q.rMeta.dbType, q.rMeta.fieldMap, order)
+	if err != nil {
+		return "", err
+	}
+
+	return "ORDER BY " + terms, nil
+}
+
 func (q *QuerySet[Resource]) Len() int {
 	return len(q.fields)
 }
@@ -65,11 +126,28 @@ func (q *QuerySet[Resource]) KeySet() KeySet {
 
 // Columns returns the database struct tags for the fields in databaseType that the user has access to view.
 func (q *QuerySet[Resource]) Columns() (Columns, error) {
+	columns, err := q.columnTags()
+	if err != nil {
+		return "", err
+	}
+
+	switch q.rMeta.dbType {
+	case SpannerDBType:
+		return Columns(strings.Join(columns, ", ")), nil
+	case PostgresDBType, SQLiteDBType:
+		return Columns(fmt.Sprintf(`"%s"`, strings.Join(columns, `", "`))), nil
+	default:
+		return "", errors.Newf("unsupported dbType: %s", q.rMeta.dbType)
+	}
+}
+
+// columnTags returns the database struct tags for the fields in struct order.
+func (q *QuerySet[Resource]) columnTags() ([]string, error) {
 	columnEntries := make([]cacheEntry, 0, q.Len())
 	for _, field := range q.Fields() {
 		c, ok := q.rMeta.fieldMap[field]
 		if !ok {
-			return "", errors.Newf("field %s not found in struct", field)
+			return nil, errors.Newf("field %s not found in struct", field)
 		}
 
 		columnEntries = append(columnEntries, c)
@@ -83,25 +161,30 @@ func (q *QuerySet[Resource]) Columns() (Columns, error) {
 		columns = append(columns, c.tag)
 	}
 
-	switch q.rMeta.dbType {
+	return columns, nil
+}
+
+// quoteIdent renders a column name or alias for dbType.
+func quoteIdent(dbType DBType, name string) (string, error) {
+	switch dbType {
 	case SpannerDBType:
-		return Columns(strings.Join(columns, ", ")), nil
-	case PostgresDBType:
-		return Columns(fmt.Sprintf(`"%s"`, strings.Join(columns, `", "`))), nil
+		return name, nil
+	case PostgresDBType, SQLiteDBType:
+		return fmt.Sprintf(`"%s"`, name), nil
 	default:
-		return "", errors.Newf("unsupported dbType: %s", q.rMeta.dbType)
+		return "", errors.Newf("unsupported dbType: %s", dbType)
 	}
 }
 
 // Where translates the the fields to database struct tags in databaseType when building the where clause
 func (q *QuerySet[Resource]) Where() (Statement, error) {
 	parts := q.KeySet().Parts()
-	if len(parts) == 0 {
+	if len(parts) == 0 && len(q.filters) == 0 {
 		return Statement{}, nil
 	}
 
 	builder := strings.Builder{}
-	params := make(map[string]any, len(parts))
+	params := make(map[string]any, len(parts)+len(q.filters))
 	for _, part := range parts {
 		c, ok := q.rMeta.fieldMap[part.Key]
 		if !ok {
@@ -111,7 +194,7 @@ func (q *QuerySet[Resource]) Where() (Statement, error) {
 		switch q.rMeta.dbType {
 		case SpannerDBType:
 			builder.WriteString(fmt.Sprintf(" AND %s = @%s", key, strings.ToLower(key)))
-		case PostgresDBType:
+		case PostgresDBType, SQLiteDBType:
 			builder.WriteString(fmt.Sprintf(` AND "%s" = @%s`, key, strings.ToLower(key)))
 		default:
 			return Statement{}, errors.Newf("unsupported dbType: %s", q.rMeta.dbType)
@@ -119,6 +202,23 @@ func (q *QuerySet[Resource]) Where() (Statement, error) {
 		params[strings.ToLower(key)] = part.Value
 	}
 
+	for i, filter := range q.filters {
+		c, ok := q.rMeta.fieldMap[filter.Field]
+		if !ok {
+			return Statement{}, errors.Newf("field %s not found in struct", filter.Field)
+		}
+
+		param := fmt.Sprintf("filter%d", i)
+		condition, value, err := filterCondition(q.rMeta.dbType, c.tag, filter, param)
+		if err != nil {
+			return Statement{}, errors.Wrap(err, "filterCondition()")
+		}
+		builder.WriteString(" AND " + condition)
+		if filter.Operator != IsNullOperator {
+			params[param] = value
+		}
+	}
+
 	return Statement{
 		Sql:    "WHERE " + builder.String()[5:],
 		Params: params,
@@ -130,98 +230,448 @@ func (q *QuerySet[Resource]) SpannerStmt() (spanner.Statement, error) {
 		return spanner.Statement{}, errors.Newf("can only use SpannerStmt() with dbType %s, got %s", SpannerDBType, q.rMeta.dbType)
 	}
 
-	if q.search != nil {
-		return q.spannerSearchStmt()
+	return q.spannerStmt(q.limit)
+}
+
+// spannerStmt builds the statement fetching at most fetch rows, or all rows when fetch is 0.
+func (q *QuerySet[Resource]) spannerStmt(fetch int) (spanner.Statement, error) {
+	if len(q.searches) > 0 {
+		return q.spannerSearchStmt(fetch)
 	}
 
-	return q.spannerIndexStmt()
+	return q.spannerIndexStmt(fetch)
 }
 
-func (q *QuerySet[Resource]) spannerIndexStmt() (spanner.Statement, error) {
+func (q *QuerySet[Resource]) spannerIndexStmt(fetch int) (spanner.Statement, error) {
 	columns, err := q.Columns()
 	if err != nil {
 		return spanner.Statement{}, errors.Wrap(err, "QuerySet.Columns()")
 	}
 
-	where, err := q.Where()
+	expansions, err := q.expansionColumns()
 	if err != nil {
-		return spanner.Statement{}, errors.Wrap(err, "patcher.Where()")
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.expansionColumns()")
+	}
+
+	where, err := q.pageWhere()
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.pageWhere()")
+	}
+
+	order, err := q.OrderByClause()
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.OrderByClause()")
+	}
+
+	limit, err := q.limitClause(fetch)
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.limitClause()")
 	}
 
 	stmt := spanner.NewStatement(fmt.Sprintf(`
 			SELECT
-				%s
+				%s%s
 			FROM %s 
-			%s`, columns, q.Resource(), where.Sql,
+			%s
+			%s
+			%s`, columns, expansions, q.spannerTable(), where.Sql, order, limit,
 	))
 	maps.Insert(stmt.Params, maps.All(where.Params))
 
 	return stmt, nil
 }
 
-func (q *QuerySet[Resource]) spannerSearchStmt() (spanner.Statement, error) {
+func (q *QuerySet[Resource]) spannerSearchStmt(fetch int) (spanner.Statement, error) {
+	if q.cursor != "" {
+		return spanner.Statement{}, httpio.NewBadRequestMessage("cursor pagination is not supported with search, use offset instead")
+	}
+
 	columns, err := q.Columns()
 	if err != nil {
 		return spanner.Statement{}, errors.Wrap(err, "QuerySet.Columns()")
 	}
 
-	var search *Statement
-	var score *Statement
+	expansions, err := q.expansionColumns()
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.expansionColumns()")
+	}
+
+	where, search, score, err := q.searchWhere()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
 
-	query := parseSpannerQuery(q.search.searchVal)
-	switch q.search.searchTyp {
-	case SubString:
-		search = query.parseToSearchSubstring(q.search.searchKey)
-		score = query.parseToNgramScore(q.search.searchKey)
-	case FullText:
-		return spanner.Statement{}, errors.New("FullText search is not yet implemented")
-	case Ngram:
-		return spanner.Statement{}, errors.New("Ngram search is not yet implemented")
+	snippets, err := q.snippetColumns()
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.snippetColumns()")
+	}
+
+	order, err := q.searchOrder()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+
+	terms, err := orderBy(q.rMeta.dbType, q.rMeta.fieldMap, order)
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "orderBy()")
+	}
+	ordering := score.Sql + " DESC, " + terms
+
+	limit, err := q.limitClause(fetch)
+	if err != nil {
+		return spanner.Statement{}, errors.Wrap(err, "QuerySet.limitClause()")
 	}
 
 	stmt := spanner.NewStatement(fmt.Sprintf(`
 			SELECT
-				%s
+				%s%s%s
 			FROM %s 
-			WHERE %s
-			ORDER BY %s DESC`,
-		columns, q.Resource(), search.Sql, score.Sql))
+			%s
+			ORDER BY %s
+			%s`,
+		columns, snippets.Sql, expansions, q.Resource(), where.Sql, ordering, limit))
 
+	maps.Insert(stmt.Params, maps.All(where.Params))
+	maps.Insert(stmt.Params, maps.All(snippets.Params))
 	maps.Insert(stmt.Params, maps.All(search.Params))
 	maps.Insert(stmt.Params, maps.All(score.Params))
 
 	return stmt, nil
 }
 
+// searchWhere returns the WHERE clause combining the search condition with the keys and filters,
+// along with the search and score statements. When several searches are set, a row matches if any of them
+// match, and the score is the weighted sum of their scores.
+func (q *QuerySet[Resource]) searchWhere() (where Statement, search, score *Statement, err error) {
+	search = &Statement{Params: make(map[string]any)}
+	score = &Statement{Params: make(map[string]any)}
+
+	searchExprs := make([]string, 0, len(q.searches))
+	scoreExprs := make([]string, 0, len(q.searches))
+	for i, set := range q.searches {
+		setSearch, setScore, err := q.searchStatements(i)
+		if err != nil {
+			return Statement{}, nil, nil, err
+		}
+
+		searchExpr, scoreExpr := setSearch.Sql, setScore.Sql
+		if len(q.searches) > 1 {
+			searchExpr = "(" + searchExpr + ")"
+		}
+		if set.weight != 1 {
+			scoreExpr = fmt.Sprintf("(%s) * %s", scoreExpr, strconv.FormatFloat(set.weight, 'g', -1, 64))
+		}
+		searchExprs = append(searchExprs, searchExpr)
+		scoreExprs = append(scoreExprs, scoreExpr)
+		maps.Insert(search.Params, maps.All(setSearch.Params))
+		maps.Insert(score.Params, maps.All(setScore.Params))
+	}
+	search.Sql = strings.Join(searchExprs, " OR ")
+	score.Sql = strings.Join(scoreExprs, " + ")
+
+	where, err = q.Where()
+	if err != nil {
+		return Statement{}, nil, nil, errors.Wrap(err, "QuerySet.Where()")
+	}
+
+	searchSql := "WHERE (" + search.Sql + ")"
+	if where.Sql != "" {
+		searchSql += " AND " + strings.TrimPrefix(where.Sql, "WHERE ")
+	}
+	where.Sql = searchSql
+
+	if where.Params == nil {
+		where.Params = make(map[string]any)
+	}
+
+	return where, search, score, nil
+}
+
+// searchStatements compiles the i-th search for the database type.
+func (q *QuerySet[Resource]) searchStatements(i int) (search, score *Statement, err error) {
+	set := q.searches[i]
+
+	switch q.rMeta.dbType {
+	case SpannerDBType:
+		query, err := q.searchQuery(i)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch set.searchTyp {
+		case SubString:
+			return query.parseToSearchSubstring(set.searchKey), query.parseToNgramScore(set.searchKey), nil
+		case FullText:
+			return query.parseToSearchFullText(set.searchKey), query.parseToFullTextScore(set.searchKey), nil
+		case Ngram:
+			return query.parseToSearchNgrams(set.searchKey), query.parseToNgramsScore(set.searchKey), nil
+		}
+	case PostgresDBType:
+		query, err := parsePostgresQuery(set.searchVal)
+		if err != nil {
+			return nil, nil, err
+		}
+		query.prefix = searchParamPrefix(i)
+
+		switch set.searchTyp {
+		case SubString:
+			return query.parseToSearchSubstring(set.searchKey), query.parseToSubstringScore(set.searchKey), nil
+		case FullText:
+			return query.parseToSearchFullText(set.searchKey), query.parseToFullTextScore(set.searchKey), nil
+		case Ngram:
+			return query.parseToSearchNgrams(set.searchKey), query.parseToNgramsScore(set.searchKey), nil
+		}
+	case SQLiteDBType:
+		query, err := parseSQLiteQuery(set.searchVal)
+		if err != nil {
+			return nil, nil, err
+		}
+		query.prefix = searchParamPrefix(i)
+
+		if set.searchTyp == SubString {
+			return query.parseToSearchSubstring(set.searchKey), query.parseToSubstringScore(set.searchKey), nil
+		}
+	default:
+		return nil, nil, errors.Newf("unsupported dbType: %s", q.rMeta.dbType)
+	}
+
+	return nil, nil, errors.Newf("unsupported search type: %s", set.searchTyp)
+}
+
+// searchQuery parses the i-th search for Spanner.
+func (q *QuerySet[Resource]) searchQuery(i int) (*spannerQueryParser, error) {
+	query, err := parseSpannerQuery(q.searches[i].searchVal)
+	if err != nil {
+		return nil, err
+	}
+	query.prefix = searchParamPrefix(i)
+
+	return query, nil
+}
+
+// searchParamPrefix namespaces the parameters of the i-th search so that several searches
+// can share a statement. The first search is not namespaced.
+func searchParamPrefix(i int) string {
+	if i == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("search%d", i)
+}
+
+// searchOrder returns the sort fields followed by the primary key, so that results
+// with equal scores are returned in a deterministic order.
+func (q *QuerySet[Resource]) searchOrder() ([]SortField, error) {
+	if len(q.rMeta.primaryKeys) == 0 {
+		return nil, errors.Newf("ordering search results requires the primary key of %s, which must implement PrimaryKeyer", q.Resource())
+	}
+
+	order := slices.Clone(q.sort)
+	for _, key := range q.rMeta.primaryKeys {
+		if !slices.ContainsFunc(order, func(s SortField) bool { return s.Field == key }) {
+			order = append(order, SortField{Field: key, Direction: Ascending})
+		}
+	}
+
+	return order, nil
+}
+
+// IncludeSnippets adds a highlighted SNIPPET column for each source field of the search key to search queries.
+// Each snippet is returned as JSON in a column named after the source column with a Snippet suffix, so the
+// destination struct needs a field such as
+//
+//	NameSnippet spanner.NullJSON `spanner:"NameSnippet"`
+func (q *QuerySet[Resource]) IncludeSnippets(include bool) *QuerySet[Resource] {
+	q.snippets = include
+
+	return q
+}
+
+func (q *QuerySet[Resource]) snippetColumns() (Statement, error) {
+	if !q.snippets || len(q.searches) == 0 {
+		return Statement{}, nil
+	}
+
+	var columns []string
+	var seen []accesstypes.Field
+	params := make(map[string]any)
+	for i, set := range q.searches {
+		if len(set.sourceFields) == 0 {
+			return Statement{}, errors.Newf("no source fields set for search key %s", set.searchKey)
+		}
+
+		query, err := q.searchQuery(i)
+		if err != nil {
+			return Statement{}, err
+		}
+		param := query.prefix + "snippetquery"
+
+		for _, field := range set.sourceFields {
+			if slices.Contains(seen, field) {
+				continue
+			}
+			seen = append(seen, field)
+
+			c, ok := q.rMeta.fieldMap[field]
+			if !ok {
+				return Statement{}, errors.Newf("field %s not found in struct", field)
+			}
+			columns = append(columns, fmt.Sprintf("SNIPPET(%s, @%s) AS %sSnippet", c.tag, param, c.tag))
+			params[param] = query.snippetQuery()
+		}
+	}
+
+	return Statement{
+		Sql:    ", " + strings.Join(columns, ", "),
+		Params: params,
+	}, nil
+}
+
 func (q *QuerySet[Resource]) PostgresStmt() (Statement, error) {
 	if q.rMeta.dbType != PostgresDBType {
 		return Statement{}, errors.Newf("can only use PostgresStmt() with dbType %s, got %s", PostgresDBType, q.rMeta.dbType)
 	}
 
+	return q.sqlStmt()
+}
+
+func (q *QuerySet[Resource]) SQLiteStmt() (Statement, error) {
+	if q.rMeta.dbType != SQLiteDBType {
+		return Statement{}, errors.Newf("can only use SQLiteStmt() with dbType %s, got %s", SQLiteDBType, q.rMeta.dbType)
+	}
+
+	return q.sqlStmt()
+}
+
+// sqlStmt builds the statement for the databases that share Postgres syntax.
+func (q *QuerySet[Resource]) sqlStmt() (Statement, error) {
+	if len(q.searches) > 0 {
+		return q.sqlSearchStmt()
+	}
+
 	columns, err := q.Columns()
 	if err != nil {
 		return Statement{}, errors.Wrap(err, "QuerySet.Columns()")
 	}
 
-	where, err := q.Where()
+	expansions, err := q.expansionColumns()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.expansionColumns()")
+	}
+
+	where, err := q.pageWhere()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.pageWhere()")
+	}
+
+	order, err := q.OrderByClause()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.OrderByClause()")
+	}
+
+	limit, err := q.limitClause(q.limit)
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.limitClause()")
+	}
+
+	table, err := q.table()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	sql := fmt.Sprintf(`
+			SELECT
+				%s%s
+			FROM %s 
+			%s
+			%s
+			%s`, columns, expansions, table, where.Sql, order, limit,
+	)
+
+	return Statement{
+		Sql:    sql,
+		Params: where.Params,
+	}, nil
+}
+
+func (q *QuerySet[Resource]) sqlSearchStmt() (Statement, error) {
+	if q.cursor != "" {
+		return Statement{}, httpio.NewBadRequestMessage("cursor pagination is not supported with search, use offset instead")
+	}
+
+	if q.snippets {
+		return Statement{}, errors.Newf("snippets are not supported with dbType %s", q.rMeta.dbType)
+	}
+
+	columns, err := q.Columns()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.Columns()")
+	}
+
+	expansions, err := q.expansionColumns()
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "QuerySet.expansionColumns()")
+	}
+
+	where, search, score, err := q.searchWhere()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	order, err := q.searchOrder()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	terms, err := orderBy(q.rMeta.dbType, q.rMeta.fieldMap, order)
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "orderBy()")
+	}
+	ordering := score.Sql + " DESC, " + terms
+
+	limit, err := q.limitClause(q.limit)
 	if err != nil {
-		return Statement{}, errors.Wrap(err, "patcher.Where()")
+		return Statement{}, errors.Wrap(err, "QuerySet.limitClause()")
+	}
+
+	table, err := q.table()
+	if err != nil {
+		return Statement{}, err
 	}
 
 	sql := fmt.Sprintf(`
 			SELECT
-				%s
+				%s%s
 			FROM %s 
-			%s`, columns, q.Resource(), where.Sql,
+			%s
+			ORDER BY %s
+			%s`, columns, expansions, table, where.Sql, ordering, limit,
 	)
 
+	maps.Insert(where.Params, maps.All(search.Params))
+	maps.Insert(where.Params, maps.All(score.Params))
+
 	return Statement{
 		Sql:    sql,
 		Params: where.Params,
 	}, nil
 }
 
+// SpannerRead reads a single row into dst. Lookups by primary key, or by the key of the index set with UseIndex,
+// use the Read API, and other queries use SQL.
 func (q *QuerySet[Resource]) SpannerRead(ctx context.Context, txn *spanner.ReadOnlyTransaction, dst any) error {
+	if err := q.checkTimestampBound(); err != nil {
+		return err
+	}
+
+	if key, index, ok := q.spannerReadKey(); ok && q.rMeta.dbType == SpannerDBType {
+		if err := q.spannerReadRow(ctx, txn, key, index, dst); err != nil {
+			return err
+		}
+
+		return q.spannerIncludes(ctx, txn, dst)
+	}
+
 	stmt, err := q.SpannerStmt()
 	if err != nil {
 		return errors.Wrap(err, "patcher.Stmt()")
@@ -235,10 +685,24 @@ func (q *QuerySet[Resource]) SpannerRead(ctx context.Context, txn *spanner.ReadO
 		return errors.Wrap(err, "spxscan.Get()")
 	}
 
-	return nil
+	return q.spannerIncludes(ctx, txn, dst)
 }
 
+// SpannerList reads all rows into dst. Lookups by primary key, or by the key of the index set with UseIndex,
+// use the Read API, and other queries use SQL.
 func (q *QuerySet[Resource]) SpannerList(ctx context.Context, txn *spanner.ReadOnlyTransaction, dst any) error {
+	if err := q.checkTimestampBound(); err != nil {
+		return err
+	}
+
+	if key, index, ok := q.spannerReadKey(); ok && q.rMeta.dbType == SpannerDBType {
+		if err := q.spannerReadRows(ctx, txn, key, index, dst); err != nil {
+			return err
+		}
+
+		return q.spannerIncludes(ctx, txn, dst)
+	}
+
 	stmt, err := q.SpannerStmt()
 	if err != nil {
 		return errors.Wrap(err, "patcher.Stmt()")
@@ -248,9 +712,102 @@ func (q *QuerySet[Resource]) SpannerList(ctx context.Context, txn *spanner.ReadO
 		return errors.Wrap(err, "spxscan.Get()")
 	}
 
-	return nil
+	return q.spannerIncludes(ctx, txn, dst)
 }
 
+// PostgresRead reads a single row into dst, a pointer to a struct with postgres tags for the selected columns.
+func (q *QuerySet[Resource]) PostgresRead(ctx context.Context, db PostgresQuerier, dst any) error {
+	stmt, err := q.PostgresStmt()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.PostgresStmt()")
+	}
+
+	rows, err := postgresQuery(ctx, db, stmt)
+	if err != nil {
+		return err
+	}
+
+	found, err := postgresGet(rows, dst)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return httpio.NewNotFoundMessagef("%s (%s) not found", q.Resource(), q.KeySet().String())
+	}
+
+	return q.postgresIncludes(ctx, db, dst)
+}
+
+// PostgresList reads all rows into dst, a pointer to a slice of structs with postgres tags for the selected columns.
+func (q *QuerySet[Resource]) PostgresList(ctx context.Context, db PostgresQuerier, dst any) error {
+	stmt, err := q.PostgresStmt()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.PostgresStmt()")
+	}
+
+	rows, err := postgresQuery(ctx, db, stmt)
+	if err != nil {
+		return err
+	}
+
+	if err := postgresSelect(rows, dst); err != nil {
+		return err
+	}
+
+	return q.postgresIncludes(ctx, db, dst)
+}
+
+// SQLiteRead reads a single row into dst, a pointer to a struct with sqlite tags for the selected columns.
+func (q *QuerySet[Resource]) SQLiteRead(ctx context.Context, db SQLiteQuerier, dst any) error {
+	stmt, err := q.SQLiteStmt()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.SQLiteStmt()")
+	}
+
+	rows, err := sqliteQuery(ctx, db, stmt)
+	if err != nil {
+		return err
+	}
+
+	found, err := sqliteGet(rows, dst)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return httpio.NewNotFoundMessagef("%s (%s) not found", q.Resource(), q.KeySet().String())
+	}
+
+	return q.sqliteIncludes(ctx, db, dst)
+}
+
+// SQLiteList reads all rows into dst, a pointer to a slice of structs with sqlite tags for the selected columns.
+func (q *QuerySet[Resource]) SQLiteList(ctx context.Context, db SQLiteQuerier, dst any) error {
+	stmt, err := q.SQLiteStmt()
+	if err != nil {
+		return errors.Wrap(err, "QuerySet.SQLiteStmt()")
+	}
+
+	rows, err := sqliteQuery(ctx, db, stmt)
+	if err != nil {
+		return err
+	}
+
+	if err := sqliteSelect(rows, dst); err != nil {
+		return err
+	}
+
+	return q.sqliteIncludes(ctx, db, dst)
+}
+
+// SetSearchParam replaces any searches with searchSet.
 func (q *QuerySet[Resource]) SetSearchParam(searchSet *SearchSet) {
-	q.search = searchSet
+	q.searches = []*SearchSet{searchSet}
+}
+
+// AddSearchParam adds searchSet to the searches. Rows matching any of the searches are
+// returned, ordered by the sum of their weighted scores.
+func (q *QuerySet[Resource]) AddSearchParam(searchSet *SearchSet) *QuerySet[Resource] {
+	q.searches = append(q.searches, searchSet)
+
+	return q
 }
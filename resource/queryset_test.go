@@ -1,7 +1,12 @@
 package resource
 
 import (
+	"reflect"
+	"strings"
+	"testing"
+
 	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
 )
 
 type SpannerStruct struct {
@@ -113,3 +118,208 @@ func (SpannerStruct) Resource() accesstypes.Resource {
 // 		})
 // 	}
 // }
+
+type searchResource struct {
+	ID   string `spanner:"Id"   query:"primaryKey"`
+	Name string `spanner:"Name"`
+}
+
+func (searchResource) Resource() accesstypes.Resource {
+	return "SearchResources"
+}
+
+func (searchResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+func TestQuerySet_SpannerStmt_search(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		searchSets []*SearchSet
+		snippets   bool
+		wantSql    string
+		wantParams map[string]any
+		wantErr    bool
+	}{
+		{
+			name:       "full text",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "NameTokens", "mike")},
+			wantSql:    "SELECT Id, Name FROM SearchResources WHERE (SEARCH(NameTokens, @fulltextquery)) ORDER BY SCORE(NameTokens, @fulltextscorequery) DESC, Id ASC",
+			wantParams: map[string]any{"fulltextquery": `"mike"`, "fulltextscorequery": `"mike"`},
+		},
+		{
+			name:       "full text with operators",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "NameTokens", `"mike smith" +john -bill`)},
+			wantSql:    "SELECT Id, Name FROM SearchResources WHERE (SEARCH(NameTokens, @fulltextquery)) ORDER BY SCORE(NameTokens, @fulltextscorequery) DESC, Id ASC",
+			wantParams: map[string]any{
+				"fulltextquery":      `"mike smith" "john" -"bill"`,
+				"fulltextscorequery": `"mike smith" OR "john"`,
+			},
+		},
+		{
+			name:       "ngram",
+			searchSets: []*SearchSet{NewSearchSet(Ngram, "NameNgrams", "mik")},
+			wantSql:    "SELECT Id, Name FROM SearchResources WHERE (SEARCH_NGRAMS(NameNgrams, @ngramsterm0)) ORDER BY SCORE_NGRAMS(NameNgrams, @ngramsterm0) DESC, Id ASC",
+			wantParams: map[string]any{"ngramsterm0": "mik"},
+		},
+		{
+			name:       "ngram with exclusion",
+			searchSets: []*SearchSet{NewSearchSet(Ngram, "NameNgrams", "mik -jon")},
+			wantSql:    "SELECT Id, Name FROM SearchResources WHERE (SEARCH_NGRAMS(NameNgrams, @ngramsterm0) AND NOT SEARCH_NGRAMS(NameNgrams, @ngramsterm1)) ORDER BY SCORE_NGRAMS(NameNgrams, @ngramsterm0) DESC, Id ASC",
+			wantParams: map[string]any{"ngramsterm0": "mik", "ngramsterm1": "jon"},
+		},
+		{
+			name:       "malformed query",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "NameTokens", `"mike`)},
+			wantErr:    true,
+		},
+		{
+			name:       "full text with snippets",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "NameTokens", "mike").SetSourceFields("Name")},
+			snippets:   true,
+			wantSql:    "SELECT Id, Name, SNIPPET(Name, @snippetquery) AS NameSnippet FROM SearchResources WHERE (SEARCH(NameTokens, @fulltextquery)) ORDER BY SCORE(NameTokens, @fulltextscorequery) DESC, Id ASC",
+			wantParams: map[string]any{
+				"fulltextquery":      `"mike"`,
+				"fulltextscorequery": `"mike"`,
+				"snippetquery":       `"mike"`,
+			},
+		},
+		{
+			name: "weighted searches",
+			searchSets: []*SearchSet{
+				NewSearchSet(FullText, "NameTokens", "mike").SetWeight(2),
+				NewSearchSet(Ngram, "NameNgrams", "mik"),
+			},
+			wantSql: "SELECT Id, Name FROM SearchResources WHERE ((SEARCH(NameTokens, @fulltextquery)) OR (SEARCH_NGRAMS(NameNgrams, @search1ngramsterm0)))" +
+				" ORDER BY (SCORE(NameTokens, @fulltextscorequery)) * 2 + SCORE_NGRAMS(NameNgrams, @search1ngramsterm0) DESC, Id ASC",
+			wantParams: map[string]any{
+				"fulltextquery":      `"mike"`,
+				"fulltextscorequery": `"mike"`,
+				"search1ngramsterm0": "mik",
+			},
+		},
+		{
+			name: "searches with snippets",
+			searchSets: []*SearchSet{
+				NewSearchSet(FullText, "NameTokens", "mike").SetSourceFields("Name"),
+				NewSearchSet(SubString, "NameSubstring", "jo").SetSourceFields("Name"),
+			},
+			snippets: true,
+			wantSql: "SELECT Id, Name, SNIPPET(Name, @snippetquery) AS NameSnippet FROM SearchResources" +
+				" WHERE ((SEARCH(NameTokens, @fulltextquery)) OR (SEARCH_SUBSTRING(NameSubstring, @search1searchsubstringterm0)))" +
+				" ORDER BY SCORE(NameTokens, @fulltextscorequery) + SCORE_NGRAMS(NameSubstring, @search1ngramscoreterm0) DESC, Id ASC",
+			wantParams: map[string]any{
+				"fulltextquery":               `"mike"`,
+				"fulltextscorequery":          `"mike"`,
+				"snippetquery":                `"mike"`,
+				"search1searchsubstringterm0": "jo",
+				"search1ngramscoreterm0":      "jo",
+			},
+		},
+		{
+			name:       "snippets without source fields",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "NameTokens", "mike")},
+			snippets:   true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := NewQuerySet(NewResourceMetadata[searchResource]()).AddField("ID").AddField("Name").IncludeSnippets(tt.snippets)
+			for _, set := range tt.searchSets {
+				q.AddSearchParam(set)
+			}
+
+			got, err := q.SpannerStmt()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuerySet.SpannerStmt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantSql, strings.Join(strings.Fields(got.SQL), " ")); diff != "" {
+				t.Errorf("QuerySet.SpannerStmt() SQL mismatch (-want +got):\n%s", diff)
+			}
+			if !reflect.DeepEqual(tt.wantParams, got.Params) {
+				t.Errorf("QuerySet.SpannerStmt() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestQuerySet_PostgresStmt_search(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		searchSets []*SearchSet
+		wantSql    string
+		wantParams map[string]any
+		wantErr    bool
+	}{
+		{
+			name:       "full text",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "status_tsv", `"in review" -closed`)},
+			wantSql: `SELECT "id", "status" FROM "FilterResources"` +
+				` WHERE ("status_tsv" @@ websearch_to_tsquery(@fulltextterm0) AND NOT "status_tsv" @@ websearch_to_tsquery(@fulltextterm1))` +
+				` ORDER BY ts_rank("status_tsv", websearch_to_tsquery(@fulltextscorequery)) DESC, "id" ASC NULLS FIRST`,
+			wantParams: map[string]any{
+				"fulltextterm0":      `"in review"`,
+				"fulltextterm1":      `"closed"`,
+				"fulltextscorequery": `"in review"`,
+			},
+		},
+		{
+			name:       "ngram",
+			searchSets: []*SearchSet{NewSearchSet(Ngram, "status", "activ pend")},
+			wantSql: `SELECT "id", "status" FROM "FilterResources" WHERE ("status" % @ngramsterm0 OR "status" % @ngramsterm1)` +
+				` ORDER BY similarity("status", @ngramsterm0) + similarity("status", @ngramsterm1) DESC, "id" ASC NULLS FIRST`,
+			wantParams: map[string]any{"ngramsterm0": "activ", "ngramsterm1": "pend"},
+		},
+		{
+			name: "substring weighted with ngram",
+			searchSets: []*SearchSet{
+				NewSearchSet(SubString, "status", "50%").SetWeight(0.5),
+				NewSearchSet(Ngram, "status", "act"),
+			},
+			wantSql: `SELECT "id", "status" FROM "FilterResources" WHERE (("status" ILIKE @searchsubstringterm0) OR ("status" % @search1ngramsterm0))` +
+				` ORDER BY ((CASE WHEN "status" ILIKE @searchsubstringterm0 THEN 1 ELSE 0 END)) * 0.5 + similarity("status", @search1ngramsterm0) DESC, "id" ASC NULLS FIRST`,
+			wantParams: map[string]any{"searchsubstringterm0": `%50\%%`, "search1ngramsterm0": "act"},
+		},
+		{
+			name:       "malformed query",
+			searchSets: []*SearchSet{NewSearchSet(FullText, "status_tsv", "active AND")},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(PostgresDBType).AddField("ID").AddField("Status")
+			for _, set := range tt.searchSets {
+				q.AddSearchParam(set)
+			}
+
+			got, err := q.PostgresStmt()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuerySet.PostgresStmt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantSql, strings.Join(strings.Fields(got.Sql), " ")); diff != "" {
+				t.Errorf("QuerySet.PostgresStmt() SQL mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantParams, got.Params); diff != "" {
+				t.Errorf("QuerySet.PostgresStmt() Params mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,11 +18,19 @@ type Resourcer interface {
 	DefaultConfig() Config
 }
 
+// PrimaryKeyer is implemented by resources to declare their primary key fields, in key order.
+// Generated resources implement it. Resources which do not fall back to the fields whose
+// query struct tag contains primaryKey, in struct order.
+type PrimaryKeyer interface {
+	PrimaryKeys() []accesstypes.Field
+}
+
 type ResourceSet[Resource Resourcer, Request any] struct {
 	permissions     []accesstypes.Permission
 	requiredTagPerm accesstypes.TagPermissions
 	fieldToTag      map[accesstypes.Field]accesstypes.Tag
 	immutableFields map[accesstypes.Tag]struct{}
+	tagDescriptions map[accesstypes.Tag]string
 	rMeta           *ResourceMetadata[Resource]
 }
 
@@ -32,11 +40,22 @@ func NewResourceSet[Resource Resourcer, Request any](permissions ...accesstypes.
 		return nil, errors.Wrap(err, "permissionsFromTags()")
 	}
 
+	tagDescriptions, err := descriptionsFromTags(reflect.TypeFor[Request]())
+	if err != nil {
+		return nil, errors.Wrap(err, "descriptionsFromTags()")
+	}
+
+	var res Resource
+	if _, err := ParseSearchKeys[Request](res); err != nil {
+		return nil, errors.Wrap(err, "ParseSearchKeys()")
+	}
+
 	return &ResourceSet[Resource, Request]{
 		permissions:     permissions,
 		requiredTagPerm: requiredTagPerm,
 		fieldToTag:      fieldToTag,
 		immutableFields: immutableFields,
+		tagDescriptions: tagDescriptions,
 		rMeta:           NewResourceMetadata[Resource](),
 	}, nil
 }
@@ -80,10 +99,26 @@ func (r *ResourceSet[Resource, Request]) Resource(fieldName accesstypes.Field) a
 	return accesstypes.Resource(fmt.Sprintf("%s.%s", res.Resource(), r.fieldToTag[fieldName]))
 }
 
+// columnResource returns the resource of the field stored in column and whether perm is required on it.
+func (r *ResourceSet[Resource, Request]) columnResource(column string, perm accesstypes.Permission) (accesstypes.Resource, bool, error) {
+	for field, c := range r.rMeta.fieldMap {
+		if c.tag == column {
+			return r.Resource(field), r.PermissionRequired(field, perm), nil
+		}
+	}
+
+	return "", false, errors.Newf("column %s not found in %s", column, r.BaseResource())
+}
+
 func (r *ResourceSet[Resource, Request]) TagPermissions() accesstypes.TagPermissions {
 	return r.requiredTagPerm
 }
 
+// TagDescriptions returns the human-readable descriptions set with the description struct tag on the Request type.
+func (r *ResourceSet[Resource, Request]) TagDescriptions() map[accesstypes.Tag]string {
+	return r.tagDescriptions
+}
+
 func permissionsFromTags(t reflect.Type, perms []accesstypes.Permission) (tags accesstypes.TagPermissions, fieldToTag map[accesstypes.Field]accesstypes.Tag, permissions []accesstypes.Permission, immutableFields map[accesstypes.Tag]struct{}, err error) {
 	if t.Kind() != reflect.Struct {
 		return nil, nil, nil, nil, errors.Newf("expected a struct, got %s", t.Kind())
@@ -97,13 +132,11 @@ func permissionsFromTags(t reflect.Type, perms []accesstypes.Permission) (tags a
 	immutableFields = make(map[accesstypes.Tag]struct{})
 
 	for _, perm := range perms {
-		switch perm {
-		case accesstypes.NullPermission:
+		if perm == accesstypes.NullPermission {
 			continue
-		case accesstypes.Create, accesstypes.Update, accesstypes.Delete:
-			mutating[perm] = struct{}{}
-		default:
-			viewing[perm] = struct{}{}
+		}
+		if err := classifyPermission(perm, mutating, viewing); err != nil {
+			return nil, nil, nil, nil, err
 		}
 		permissionMap[perm] = struct{}{}
 	}
@@ -121,14 +154,14 @@ func permissionsFromTags(t reflect.Type, perms []accesstypes.Permission) (tags a
 				continue
 			case accesstypes.Delete:
 				return nil, nil, nil, nil, errors.Newf("delete permission is not allowed in struct tag")
-			case accesstypes.Create, accesstypes.Update:
-				mutating[permission] = struct{}{}
 			case accesstypes.Permission("Immutable"):
 				immutableFields[accesstypes.Tag(jsonTag)] = struct{}{}
 				permission = accesstypes.Update
 				mutating[permission] = struct{}{}
 			default:
-				viewing[permission] = struct{}{}
+				if err := classifyPermission(permission, mutating, viewing); err != nil {
+					return nil, nil, nil, nil, err
+				}
 			}
 
 			if jsonTag == "" || jsonTag == "-" {
@@ -161,8 +194,58 @@ func permissionsFromTags(t reflect.Type, perms []accesstypes.Permission) (tags a
 	return tags, fieldToTag, permissions, immutableFields, nil
 }
 
+// descriptionsFromTags collects the description struct tags on t keyed by json tag.
+// It returns nil when no field has a description.
+func descriptionsFromTags(t reflect.Type) (map[accesstypes.Tag]string, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Newf("expected a struct, got %s", t.Kind())
+	}
+
+	var descriptions map[accesstypes.Tag]string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		description := strings.TrimSpace(field.Tag.Get("description"))
+		if description == "" {
+			continue
+		}
+
+		jsonTag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonTag == "" || jsonTag == "-" {
+			return nil, errors.Newf("can not set a description on the %s field when json tag is empty", field.Name)
+		}
+
+		if descriptions == nil {
+			descriptions = make(map[accesstypes.Tag]string)
+		}
+		descriptions[accesstypes.Tag(jsonTag)] = description
+	}
+
+	return descriptions, nil
+}
+
+// classifyPermission records perm in mutating or viewing based on its registered kind.
+// Action permissions are not restricted in how they combine with other permissions, so they are not recorded.
+// Permissions which have not been registered are rejected, since their kind is not known yet.
+func classifyPermission(perm accesstypes.Permission, mutating, viewing map[accesstypes.Permission]struct{}) error {
+	detail, ok := accesstypes.LookupPermission(perm)
+	if !ok {
+		return errors.Newf("permission %s is not registered, register it with accesstypes.RegisterPermission() before creating the resource set", perm)
+	}
+
+	switch detail.Kind {
+	case accesstypes.MutatingPermissionKind:
+		mutating[perm] = struct{}{}
+	case accesstypes.ActionPermissionKind:
+	default:
+		viewing[perm] = struct{}{}
+	}
+
+	return nil
+}
+
 type ResourceMetadata[Resource Resourcer] struct {
 	fieldMap            map[accesstypes.Field]cacheEntry
+	primaryKeys         []accesstypes.Field
 	dbType              DBType
 	changeTrackingTable string
 	trackChanges        bool
@@ -175,6 +258,7 @@ func NewResourceMetadata[Resource Resourcer]() *ResourceMetadata[Resource] {
 
 	return &ResourceMetadata[Resource]{
 		fieldMap:            c.fieldMap,
+		primaryKeys:         c.primaryKeys,
 		dbType:              c.cfg.DBType,
 		changeTrackingTable: c.cfg.ChangeTrackingTable,
 		trackChanges:        c.cfg.TrackChanges,
@@ -186,8 +270,9 @@ var resMetadataCache = resourceMetadataCache{
 }
 
 type resourceMetadataCacheEntry struct {
-	fieldMap map[accesstypes.Field]cacheEntry
-	cfg      Config
+	fieldMap    map[accesstypes.Field]cacheEntry
+	primaryKeys []accesstypes.Field
+	cfg         Config
 }
 
 type resourceMetadataCache struct {
@@ -229,14 +314,35 @@ func (c *resourceMetadataCache) get(res Resourcer) *resourceMetadataCacheEntry {
 	}
 	fieldMap := structTags(t, string(cfg.DBType))
 
+	var primaryKeys []accesstypes.Field
+	if pk, ok := res.(PrimaryKeyer); ok {
+		primaryKeys = pk.PrimaryKeys()
+	} else {
+		primaryKeys = primaryKeyFields(t)
+	}
+
 	c.cache[t] = &resourceMetadataCacheEntry{
-		fieldMap: fieldMap,
-		cfg:      cfg,
+		fieldMap:    fieldMap,
+		primaryKeys: primaryKeys,
+		cfg:         cfg,
 	}
 
 	return c.cache[t]
 }
 
+// primaryKeyFields returns the fields of t whose query struct tag contains primaryKey, in struct order.
+func primaryKeyFields(t reflect.Type) []accesstypes.Field {
+	var fields []accesstypes.Field
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if slices.Contains(strings.Split(field.Tag.Get("query"), ","), "primaryKey") {
+			fields = append(fields, accesstypes.Field(field.Name))
+		}
+	}
+
+	return fields
+}
+
 func structTags(t reflect.Type, key string) map[accesstypes.Field]cacheEntry {
 	tagMap := make(map[accesstypes.Field]cacheEntry)
 	for i := range t.NumField() {
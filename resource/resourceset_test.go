@@ -49,6 +49,34 @@ type HRequest struct {
 	Field3 string `json:"field3"`
 }
 
+var (
+	testApprove = accesstypes.MustRegisterPermission("Approve", accesstypes.PermissionDetail{Description: "Approve a resource", Kind: accesstypes.ActionPermissionKind})
+	testArchive = accesstypes.MustRegisterPermission("Archive", accesstypes.PermissionDetail{Description: "Archive a resource", Kind: accesstypes.MutatingPermissionKind})
+)
+
+type MRequest struct {
+	Field1 string `json:"field1" perm:"Unregistered"`
+}
+
+type IRequest struct {
+	Field1 string `json:"field1" perm:"Approve"`
+	Field2 string `json:"field2" perm:"Approve,Update"`
+}
+
+type JRequest struct {
+	Field1 string `json:"field1" perm:"Archive"`
+	Field2 string `json:"field2" perm:"Read"`
+}
+
+type KRequest struct {
+	Field1 string `json:"field1" perm:"Read" description:"The first field"`
+	Field2 string `json:"field2" perm:"Read"`
+}
+
+type LRequest struct {
+	Field1 string `json:"-" description:"An ignored field"`
+}
+
 type AResource struct {
 	Field1 string `json:"Field1"`
 	Field2 string `json:"Field2"`
@@ -144,6 +172,41 @@ func TestNewResourceSet(t *testing.T) {
 				wantErr: true,
 			},
 		},
+		{
+			name:   "New with action permission alongside mutating permission",
+			testFn: testNewResourceSetRun[AResource, IRequest],
+			wants: wantResourceSetRun{
+				wantPermissions: []accesstypes.Permission{testApprove, accesstypes.Update},
+				requiredTagPerm: accesstypes.TagPermissions{"field1": {testApprove}, "field2": {testApprove, accesstypes.Update}},
+				fieldToTag:      map[accesstypes.Field]accesstypes.Tag{"Field1": "field1", "Field2": "field2"},
+				immutableFields: map[accesstypes.Tag]struct{}{},
+			},
+		},
+		{
+			name:   "New with registered mutating permission mixed with viewing permission",
+			testFn: testNewResourceSetRun[AResource, JRequest],
+			wants: wantResourceSetRun{
+				wantErr: true,
+			},
+		},
+		{
+			name:   "New with tag description",
+			testFn: testNewResourceSetRun[AResource, KRequest],
+			wants: wantResourceSetRun{
+				wantPermissions: []accesstypes.Permission{accesstypes.Read},
+				requiredTagPerm: accesstypes.TagPermissions{"field1": {accesstypes.Read}, "field2": {accesstypes.Read}},
+				fieldToTag:      map[accesstypes.Field]accesstypes.Tag{"Field1": "field1", "Field2": "field2"},
+				immutableFields: map[accesstypes.Tag]struct{}{},
+				tagDescriptions: map[accesstypes.Tag]string{"field1": "The first field"},
+			},
+		},
+		{
+			name:   "New with description on ignored field",
+			testFn: testNewResourceSetRun[AResource, LRequest],
+			wants: wantResourceSetRun{
+				wantErr: true,
+			},
+		},
 		{
 			name:   "New with permission on ignored field",
 			testFn: testNewResourceSetRun[AResource, GRequest],
@@ -151,6 +214,23 @@ func TestNewResourceSet(t *testing.T) {
 				wantErr: true,
 			},
 		},
+		{
+			name:   "New with unregistered tag permission",
+			testFn: testNewResourceSetRun[AResource, MRequest],
+			wants: wantResourceSetRun{
+				wantErr: true,
+			},
+		},
+		{
+			name: "New with unregistered permission",
+			args: args{
+				permissions: []accesstypes.Permission{"Unregistered"},
+			},
+			testFn: testNewResourceSetRun[AResource, HRequest],
+			wants: wantResourceSetRun{
+				wantErr: true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -163,6 +243,7 @@ type wantResourceSetRun struct {
 	requiredTagPerm accesstypes.TagPermissions
 	fieldToTag      map[accesstypes.Field]accesstypes.Tag
 	immutableFields map[accesstypes.Tag]struct{}
+	tagDescriptions map[accesstypes.Tag]string
 	wantErr         bool
 }
 
@@ -174,6 +255,7 @@ func testNewResourceSetRun[Resource Resourcer, Request any](t *testing.T, name s
 			requiredTagPerm: w.requiredTagPerm,
 			fieldToTag:      w.fieldToTag,
 			immutableFields: w.immutableFields,
+			tagDescriptions: w.tagDescriptions,
 			rMeta:           NewResourceMetadata[Resource](),
 		}
 	}
@@ -232,3 +314,42 @@ func BenchmarkNewResourceMetadata(b *testing.B) {
 		_ = NewResourceMetadata[DoeInstitution]()
 	}
 }
+
+type keyedResource struct {
+	Region string `spanner:"Region"`
+	ID     string `spanner:"Id"`
+}
+
+func (keyedResource) Resource() accesstypes.Resource {
+	return "KeyedResources"
+}
+
+func (keyedResource) DefaultConfig() Config {
+	return Config{DBType: SpannerDBType}
+}
+
+func (keyedResource) PrimaryKeys() []accesstypes.Field {
+	return []accesstypes.Field{"ID", "Region"}
+}
+
+func TestNewResourceMetadata_primaryKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		got  func() []accesstypes.Field
+		want []accesstypes.Field
+	}{
+		{name: "primary keyer", got: func() []accesstypes.Field { return NewResourceMetadata[keyedResource]().primaryKeys }, want: []accesstypes.Field{"ID", "Region"}},
+		{name: "query tag", got: func() []accesstypes.Field { return NewResourceMetadata[filterResource]().primaryKeys }, want: []accesstypes.Field{"ID"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, tt.got()); diff != "" {
+				t.Errorf("NewResourceMetadata() primaryKeys mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,29 +3,348 @@ package resource
 import (
 	"fmt"
 	"strings"
+	"unicode"
+
+	"github.com/cccteam/httpio"
 )
 
+// searchTerm is a single word or quoted phrase in a search query.
+type searchTerm struct {
+	index    int
+	text     string
+	phrase   bool
+	required bool
+	excluded bool
+}
+
+// searchClause is a run of adjacent terms. Plain terms are combined with OR, and
+// the result is further constrained by the required and excluded terms.
+type searchClause []searchTerm
+
+// searchQuery is the parsed form of a search string: clauses joined by AND form a group,
+// and groups are joined by OR.
+type searchQuery [][]searchClause
+
+// spannerQueryParser compiles a search string to Spanner search functions.
+//
+// The query language supports:
+//
+//	mike john        either term
+//	+mike +john      both terms
+//	mike -john       mike, but not john
+//	"mike smith"     the exact phrase
+//	mike AND john    both sides
+//	mike OR john     either side, with AND binding tighter than OR
+//	\"mike \-john    a backslash escapes the next character
 type spannerQueryParser struct {
 	query string
+	terms searchQuery
+	// prefix namespaces the parameter names when several searches share a statement
+	prefix string
+}
+
+func parseSpannerQuery(query string) (*spannerQueryParser, error) {
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spannerQueryParser{query: query, terms: terms}, nil
 }
 
-func parseSpannerQuery(query string) *spannerQueryParser {
-	return &spannerQueryParser{query: query}
+type searchToken struct {
+	term     searchTerm
+	operator string
 }
 
-func (s spannerQueryParser) parseToSearchSubstring(tokenlist SearchKey) *Statement {
-	terms := strings.Split(s.query, " ")
+func tokenizeSearchQuery(query string) ([]searchToken, error) {
+	runes := []rune(query)
+
+	var tokens []searchToken
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+
+			continue
+		}
+
+		var term searchTerm
+		switch runes[i] {
+		case '+':
+			term.required = true
+			i++
+		case '-':
+			term.excluded = true
+			i++
+		}
+		if term.required || term.excluded {
+			if i == len(runes) || unicode.IsSpace(runes[i]) {
+				return nil, httpio.NewBadRequestMessagef("invalid search query: %q must be followed by a term", runes[i-1])
+			}
+		}
+
+		var text strings.Builder
+		var escaped bool
+		if runes[i] == '"' {
+			term.phrase = true
+			i++
+			closed := false
+			for ; i < len(runes); i++ {
+				switch r := runes[i]; {
+				case r == '\\' && i+1 < len(runes):
+					i++
+					text.WriteRune(runes[i])
+				case r == '\\':
+					return nil, httpio.NewBadRequestMessage("invalid search query: trailing escape character")
+				case r == '"':
+					closed = true
+				default:
+					text.WriteRune(r)
+				}
+				if closed {
+					i++
+
+					break
+				}
+			}
+			if !closed {
+				return nil, httpio.NewBadRequestMessage("invalid search query: unterminated quoted phrase")
+			}
+			if strings.TrimSpace(text.String()) == "" {
+				return nil, httpio.NewBadRequestMessage("invalid search query: empty quoted phrase")
+			}
+			term.text = strings.Join(strings.Fields(text.String()), " ")
+		} else {
+			for ; i < len(runes) && !unicode.IsSpace(runes[i]); i++ {
+				switch r := runes[i]; {
+				case r == '\\' && i+1 < len(runes):
+					i++
+					escaped = true
+					text.WriteRune(runes[i])
+				case r == '\\':
+					return nil, httpio.NewBadRequestMessage("invalid search query: trailing escape character")
+				case r == '"':
+					return nil, httpio.NewBadRequestMessagef("invalid search query: unexpected quote in %q", string(runes[:i+1]))
+				default:
+					text.WriteRune(r)
+				}
+			}
+			term.text = text.String()
+		}
+
+		if !term.phrase && !escaped && !term.required && !term.excluded && (term.text == "AND" || term.text == "OR") {
+			tokens = append(tokens, searchToken{operator: term.text})
+
+			continue
+		}
+
+		tokens = append(tokens, searchToken{term: term})
+	}
+
+	return tokens, nil
+}
+
+func parseSearchQuery(query string) (searchQuery, error) {
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		terms  searchQuery
+		group  []searchClause
+		clause searchClause
+		index  int
+	)
+	for _, token := range tokens {
+		if token.operator == "" {
+			token.term.index = index
+			index++
+			clause = append(clause, token.term)
+
+			continue
+		}
+
+		if len(clause) == 0 {
+			return nil, httpio.NewBadRequestMessagef("invalid search query: %s must be between two terms", token.operator)
+		}
+
+		group = append(group, clause)
+		clause = nil
+		if token.operator == "OR" {
+			terms = append(terms, group)
+			group = nil
+		}
+	}
+
+	if len(clause) == 0 {
+		if len(tokens) > 0 {
+			return nil, httpio.NewBadRequestMessagef("invalid search query: %s must be between two terms", tokens[len(tokens)-1].operator)
+		}
+
+		return nil, httpio.NewBadRequestMessage("invalid search query: query is empty")
+	}
+
+	return append(terms, append(group, clause)), nil
+}
+
+// positive returns the terms which are not excluded.
+func (q searchQuery) positive() []searchTerm {
+	var terms []searchTerm
+	for _, group := range q {
+		for _, clause := range group {
+			for _, term := range clause {
+				if !term.excluded {
+					terms = append(terms, term)
+				}
+			}
+		}
+	}
+
+	return terms
+}
+
+// render compiles the query to a boolean expression, using term to render each term.
+func (q searchQuery) render(term func(t searchTerm) string) string {
+	groups := make([]string, 0, len(q))
+	for _, group := range q {
+		clauses := make([]string, 0, len(group))
+		for _, clause := range group {
+			sql, or := clause.render(term)
+			if or && len(group) > 1 {
+				sql = "(" + sql + ")"
+			}
+			clauses = append(clauses, sql)
+		}
+		groups = append(groups, strings.Join(clauses, " AND "))
+	}
+
+	return strings.Join(groups, " OR ")
+}
+
+// render compiles the clause, reporting whether the result is a top level OR.
+func (c searchClause) render(term func(t searchTerm) string) (sql string, or bool) {
+	var either, all []string
+	for _, t := range c {
+		switch {
+		case t.excluded:
+			all = append(all, "NOT "+term(t))
+		case t.required:
+			all = append(all, term(t))
+		default:
+			either = append(either, term(t))
+		}
+	}
+
+	if len(either) == 0 {
+		return strings.Join(all, " AND "), false
+	}
+
+	eitherSql := strings.Join(either, " OR ")
+	if len(all) == 0 {
+		return eitherSql, len(either) > 1
+	}
+	if len(either) > 1 {
+		eitherSql = "(" + eitherSql + ")"
+	}
+
+	return strings.Join(append([]string{eitherSql}, all...), " AND "), false
+}
+
+// sum adds up the expressions rendered for the terms which are not excluded.
+func (q searchQuery) sum(term func(t searchTerm) (sql, param string, value any)) *Statement {
+	terms := q.positive()
+	if len(terms) == 0 {
+		return &Statement{Sql: "0", Params: map[string]any{}}
+	}
 
 	exprs := make([]string, 0, len(terms))
 	params := make(map[string]any, len(terms))
-	for i, term := range terms {
-		param := fmt.Sprintf("searchsubstringterm%d", i)
+	for _, t := range terms {
+		sql, param, value := term(t)
+		params[param] = value
+		exprs = append(exprs, sql)
+	}
 
-		params[param] = term
+	return &Statement{
+		Sql:    strings.Join(exprs, " + "),
+		Params: params,
+	}
+}
 
-		exprs = append(exprs, fmt.Sprintf("SEARCH_SUBSTRING(%s, @%s)", tokenlist, param))
+// rquery renders the query in the Spanner raw search query syntax used by SEARCH, SCORE and SNIPPET.
+func (q searchQuery) rquery() string {
+	groups := make([]string, 0, len(q))
+	for _, group := range q {
+		clauses := make([]string, 0, len(group))
+		for _, clause := range group {
+			var either, all []string
+			for _, t := range clause {
+				switch {
+				case t.excluded:
+					all = append(all, "-"+quoteRQueryTerm(t.text))
+				case t.required:
+					all = append(all, quoteRQueryTerm(t.text))
+				default:
+					either = append(either, quoteRQueryTerm(t.text))
+				}
+			}
+			if len(either) > 0 {
+				eitherQuery := strings.Join(either, " OR ")
+				if len(either) > 1 && (len(all) > 0 || len(group) > 1) {
+					eitherQuery = "(" + eitherQuery + ")"
+				}
+				all = append([]string{eitherQuery}, all...)
+			}
+			clauses = append(clauses, all...)
+		}
+
+		groupQuery := strings.Join(clauses, " ")
+		if len(clauses) > 1 && len(q) > 1 {
+			groupQuery = "(" + groupQuery + ")"
+		}
+		groups = append(groups, groupQuery)
 	}
-	sql := strings.Join(exprs, " OR ")
+
+	return strings.Join(groups, " OR ")
+}
+
+var rqueryEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteRQueryTerm quotes text so that operators and punctuation in it are matched literally.
+func quoteRQueryTerm(text string) string {
+	return `"` + rqueryEscaper.Replace(text) + `"`
+}
+
+// anyQuery returns a query matching any of the terms which are not excluded, with
+// each term quoted and the terms joined by or.
+func (q searchQuery) anyQuery(or string) string {
+	terms := q.positive()
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		quoted = append(quoted, quoteRQueryTerm(t.text))
+	}
+
+	return strings.Join(quoted, " "+or+" ")
+}
+
+// snippetQuery returns the raw search query used to highlight the matching terms.
+func (s spannerQueryParser) snippetQuery() string {
+	return s.terms.anyQuery("OR")
+}
+
+func (s spannerQueryParser) parseToSearchSubstring(tokenlist SearchKey) *Statement {
+	params := make(map[string]any)
+	sql := s.terms.render(func(t searchTerm) string {
+		param := fmt.Sprintf("%ssearchsubstringterm%d", s.prefix, t.index)
+		params[param] = t.text
+
+		if t.phrase {
+			return fmt.Sprintf("SEARCH_SUBSTRING(%s, @%s, relative_search_type=>'phrase')", tokenlist, param)
+		}
+
+		return fmt.Sprintf("SEARCH_SUBSTRING(%s, @%s)", tokenlist, param)
+	})
 
 	return &Statement{
 		Sql:    sql,
@@ -34,20 +353,51 @@ func (s spannerQueryParser) parseToSearchSubstring(tokenlist SearchKey) *Stateme
 }
 
 func (s spannerQueryParser) parseToNgramScore(tokenlist SearchKey) *Statement {
-	terms := strings.Split(s.query, " ")
+	return s.ngramsScore(tokenlist, "ngramscoreterm")
+}
 
-	exprs := make([]string, 0, len(terms))
-	params := make(map[string]any, len(terms))
-	for i, term := range terms {
-		param := fmt.Sprintf("ngramscoreterm%d", i)
-		params[param] = term
+func (s spannerQueryParser) parseToSearchFullText(tokenlist SearchKey) *Statement {
+	param := s.prefix + "fulltextquery"
+
+	return &Statement{
+		Sql:    fmt.Sprintf("SEARCH(%s, @%s)", tokenlist, param),
+		Params: map[string]any{param: s.terms.rquery()},
+	}
+}
+
+func (s spannerQueryParser) parseToFullTextScore(tokenlist SearchKey) *Statement {
+	param := s.prefix + "fulltextscorequery"
 
-		exprs = append(exprs, fmt.Sprintf("SCORE_NGRAMS(%s, @%s)", tokenlist, param))
+	return &Statement{
+		Sql:    fmt.Sprintf("SCORE(%s, @%s)", tokenlist, param),
+		Params: map[string]any{param: s.snippetQuery()},
 	}
-	sql := strings.Join(exprs, " + ")
+}
+
+func (s spannerQueryParser) parseToSearchNgrams(tokenlist SearchKey) *Statement {
+	params := make(map[string]any)
+	sql := s.terms.render(func(t searchTerm) string {
+		param := fmt.Sprintf("%sngramsterm%d", s.prefix, t.index)
+		params[param] = t.text
+
+		return fmt.Sprintf("SEARCH_NGRAMS(%s, @%s)", tokenlist, param)
+	})
 
 	return &Statement{
 		Sql:    sql,
 		Params: params,
 	}
 }
+
+func (s spannerQueryParser) parseToNgramsScore(tokenlist SearchKey) *Statement {
+	return s.ngramsScore(tokenlist, "ngramsterm")
+}
+
+// ngramsScore sums SCORE_NGRAMS over the terms which are not excluded.
+func (s spannerQueryParser) ngramsScore(tokenlist SearchKey, name string) *Statement {
+	return s.terms.sum(func(t searchTerm) (string, string, any) {
+		param := fmt.Sprintf("%s%s%d", s.prefix, name, t.index)
+
+		return fmt.Sprintf("SCORE_NGRAMS(%s, @%s)", tokenlist, param), param, t.text
+	})
+}
@@ -53,8 +53,9 @@ func Test_spannerQueryParser_parseToSearchSubstring(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			s := spannerQueryParser{
-				query: tt.query,
+			s, err := parseSpannerQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parseSpannerQuery() error = %v", err)
 			}
 			if got := s.parseToSearchSubstring(tt.tokenlist); !reflect.DeepEqual(*got, tt.want) {
 				t.Errorf("spannerQueryParser.parseToSearchSubstring() = %v, want %v", *got, tt.want)
@@ -118,8 +119,9 @@ func Test_spannerQueryParser_parseToNgramScore(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			s := spannerQueryParser{
-				query: tt.query,
+			s, err := parseSpannerQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parseSpannerQuery() error = %v", err)
 			}
 			if got := s.parseToNgramScore(tt.tokenlist); !reflect.DeepEqual(*got, tt.want) {
 				t.Errorf("spannerQueryParser.parseToNgramScore() = %v, want %v", *got, tt.want)
@@ -137,3 +139,106 @@ func Test_spannerQueryParser_parseToNgramScore(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseSpannerQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		query         string
+		wantSubstring Statement
+		wantRQuery    string
+		wantErr       bool
+	}{
+		{
+			name:  "repeated spaces",
+			query: "  mike   john ",
+			wantSubstring: Statement{
+				Sql:    "SEARCH_SUBSTRING(NameTokens, @searchsubstringterm0) OR SEARCH_SUBSTRING(NameTokens, @searchsubstringterm1)",
+				Params: map[string]any{"searchsubstringterm0": "mike", "searchsubstringterm1": "john"},
+			},
+			wantRQuery: `"mike" OR "john"`,
+		},
+		{
+			name:  "phrase",
+			query: `"mike  smith"`,
+			wantSubstring: Statement{
+				Sql:    "SEARCH_SUBSTRING(NameTokens, @searchsubstringterm0, relative_search_type=>'phrase')",
+				Params: map[string]any{"searchsubstringterm0": "mike smith"},
+			},
+			wantRQuery: `"mike smith"`,
+		},
+		{
+			name:  "required and excluded",
+			query: "mike john +smith -bill",
+			wantSubstring: Statement{
+				Sql: "(SEARCH_SUBSTRING(NameTokens, @searchsubstringterm0) OR SEARCH_SUBSTRING(NameTokens, @searchsubstringterm1))" +
+					" AND SEARCH_SUBSTRING(NameTokens, @searchsubstringterm2) AND NOT SEARCH_SUBSTRING(NameTokens, @searchsubstringterm3)",
+				Params: map[string]any{
+					"searchsubstringterm0": "mike",
+					"searchsubstringterm1": "john",
+					"searchsubstringterm2": "smith",
+					"searchsubstringterm3": "bill",
+				},
+			},
+			wantRQuery: `("mike" OR "john") "smith" -"bill"`,
+		},
+		{
+			name:  "AND binds tighter than OR",
+			query: "mike AND john OR bill",
+			wantSubstring: Statement{
+				Sql: "SEARCH_SUBSTRING(NameTokens, @searchsubstringterm0) AND SEARCH_SUBSTRING(NameTokens, @searchsubstringterm1)" +
+					" OR SEARCH_SUBSTRING(NameTokens, @searchsubstringterm2)",
+				Params: map[string]any{
+					"searchsubstringterm0": "mike",
+					"searchsubstringterm1": "john",
+					"searchsubstringterm2": "bill",
+				},
+			},
+			wantRQuery: `("mike" "john") OR "bill"`,
+		},
+		{
+			name:  "escaping",
+			query: `\-mike \AND \"john\"`,
+			wantSubstring: Statement{
+				Sql: "SEARCH_SUBSTRING(NameTokens, @searchsubstringterm0) OR SEARCH_SUBSTRING(NameTokens, @searchsubstringterm1)" +
+					" OR SEARCH_SUBSTRING(NameTokens, @searchsubstringterm2)",
+				Params: map[string]any{
+					"searchsubstringterm0": "-mike",
+					"searchsubstringterm1": "AND",
+					"searchsubstringterm2": `"john"`,
+				},
+			},
+			wantRQuery: `"-mike" OR "AND" OR "\"john\""`,
+		},
+		{name: "empty", query: "   ", wantErr: true},
+		{name: "unterminated phrase", query: `"mike smith`, wantErr: true},
+		{name: "empty phrase", query: `mike ""`, wantErr: true},
+		{name: "leading operator", query: "OR mike", wantErr: true},
+		{name: "trailing operator", query: "mike AND", wantErr: true},
+		{name: "adjacent operators", query: "mike AND OR john", wantErr: true},
+		{name: "dangling exclusion", query: "mike -", wantErr: true},
+		{name: "trailing escape", query: `mike\`, wantErr: true},
+		{name: "quote inside term", query: `mi"ke`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := parseSpannerQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSpannerQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantSubstring, *s.parseToSearchSubstring("NameTokens")); diff != "" {
+				t.Errorf("parseToSearchSubstring() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRQuery, s.terms.rquery()); diff != "" {
+				t.Errorf("rquery() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,24 +2,51 @@ package resource
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/go-playground/errors/v5"
 )
 
 type SearchKeys struct {
-	keys map[SearchKey]SearchType
+	dbType  DBType
+	keys    map[SearchKey]SearchType
+	sources map[SearchKey][]accesstypes.Field
+	weights map[SearchKey]float64
 }
 
+// NewSearchKeys returns the search keys declared by the search struct tags of Req.
+//
+// Deprecated: Use ParseSearchKeys, which returns an error for invalid search tags. NewSearchKeys panics on them.
 func NewSearchKeys[Req any](res Resourcer) *SearchKeys {
+	searchKeys, err := ParseSearchKeys[Req](res)
+	if err != nil {
+		panic(err)
+	}
+
+	return searchKeys
+}
+
+// ParseSearchKeys returns the search keys declared by the search struct tags of Req. It returns an
+// error for a malformed weight, or for a search key given different weights on different fields.
+func ParseSearchKeys[Req any](res Resourcer) (*SearchKeys, error) {
 	var searchTypes []SearchType
 
-	switch res.DefaultConfig().DBType {
+	dbType := res.DefaultConfig().DBType
+	switch dbType {
 	case SpannerDBType:
 		searchTypes = []SearchType{FullText, Ngram, SubString}
 	case PostgresDBType:
-		searchTypes = []SearchType{}
+		searchTypes = []SearchType{FullText, Ngram, SubString}
+	case SQLiteDBType:
+		searchTypes = []SearchType{SubString}
 	}
 
 	keys := make(map[SearchKey]SearchType, 0)
+	sources := make(map[SearchKey][]accesstypes.Field)
+	weights := make(map[SearchKey]float64)
+	declared := make(map[SearchKey]float64)
 	for _, structField := range reflect.VisibleFields(reflect.TypeFor[Req]()) {
 		for _, searchType := range searchTypes {
 			keyList := structField.Tag.Get(string(searchType))
@@ -28,14 +55,54 @@ func NewSearchKeys[Req any](res Resourcer) *SearchKeys {
 			}
 
 			for _, key := range splitSearchKeys(keyList) {
+				key, weight, err := searchKeyWeight(key)
+				if err != nil {
+					return nil, errors.Wrapf(err, "%s tag on field %s", searchType, structField.Name)
+				}
+				if w, ok := declared[key]; ok && w != weight {
+					return nil, errors.Newf("search key %s has weight %g on field %s and %g on another field", key, weight, structField.Name, w)
+				}
+				declared[key] = weight
+				if weight != 1 {
+					weights[key] = weight
+				}
 				keys[key] = searchType
+				sources[key] = append(sources[key], accesstypes.Field(structField.Name))
 			}
 		}
 	}
 
 	return &SearchKeys{
-		keys: keys,
+		dbType:  dbType,
+		keys:    keys,
+		sources: sources,
+		weights: weights,
+	}, nil
+}
+
+// searchKeyWeight splits an optional relevance weight from a search key, written as
+// Key:weight in the struct tag. Keys without a weight have a weight of 1.
+func searchKeyWeight(key SearchKey) (SearchKey, float64, error) {
+	name, weight, found := strings.Cut(string(key), ":")
+	if !found {
+		return key, 1, nil
+	}
+
+	w, err := strconv.ParseFloat(weight, 64)
+	if err != nil || w <= 0 {
+		return "", 0, errors.Newf("invalid weight %q for search key %s, expected a positive number", weight, name)
+	}
+
+	return SearchKey(name), w, nil
+}
+
+// weight returns the relevance weight of key.
+func (s *SearchKeys) weight(key SearchKey) float64 {
+	if w, ok := s.weights[key]; ok {
+		return w
 	}
+
+	return 1
 }
 
 func splitSearchKeys(keys string) []SearchKey {
@@ -1,9 +1,13 @@
 package resource
 
+import "github.com/cccteam/ccc/accesstypes"
+
 type SearchSet struct {
-	searchTyp SearchType
-	searchKey SearchKey
-	searchVal string
+	searchTyp    SearchType
+	searchKey    SearchKey
+	searchVal    string
+	sourceFields []accesstypes.Field
+	weight       float64
 }
 
 func NewSearchSet(searchTyp SearchType, searchKey SearchKey, searchVal string) *SearchSet {
@@ -11,5 +15,22 @@ func NewSearchSet(searchTyp SearchType, searchKey SearchKey, searchVal string) *
 		searchTyp: searchTyp,
 		searchKey: searchKey,
 		searchVal: searchVal,
+		weight:    1,
 	}
 }
+
+// SetSourceFields sets the fields whose columns are tokenized into the search key.
+// They are required to return snippets with QuerySet.IncludeSnippets.
+func (s *SearchSet) SetSourceFields(fields ...accesstypes.Field) *SearchSet {
+	s.sourceFields = fields
+
+	return s
+}
+
+// SetWeight sets the factor applied to the relevance score of this search when
+// several searches are combined in one query. The default weight is 1.
+func (s *SearchSet) SetWeight(weight float64) *SearchSet {
+	s.weight = weight
+
+	return s
+}
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	"context"
+
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
+)
+
+type serviceAccountCtxKey struct{}
+
+// ServiceAccountInfo identifies the service account that authenticated a request, for example with an API key.
+//
+// While a context carries a ServiceAccountInfo, permission checks in the decoders evaluate the grants of
+// ServiceAccount with accesstypes.ServiceAccountEnforcer in place of the user, and UserEvent records
+// the service account.
+type ServiceAccountInfo struct {
+	ServiceAccount accesstypes.ServiceAccount
+	// ID is the ID of the service account recorded in change tracking
+	ID ccc.UUID
+}
+
+// WithServiceAccount returns a copy of ctx authenticated as info.
+func WithServiceAccount(ctx context.Context, info ServiceAccountInfo) context.Context {
+	return context.WithValue(ctx, serviceAccountCtxKey{}, info)
+}
+
+// ServiceAccountFromCtx returns the ServiceAccountInfo stored in ctx, if any.
+func ServiceAccountFromCtx(ctx context.Context) (ServiceAccountInfo, bool) {
+	info, ok := ctx.Value(serviceAccountCtxKey{}).(ServiceAccountInfo)
+
+	return info, ok
+}
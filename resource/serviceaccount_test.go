@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cccteam/ccc"
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/ccc/accesstypes/accesstypestest"
+	"github.com/cccteam/ccc/resource/mock/mock_accesstypes"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_requireResources_serviceAccount(t *testing.T) {
+	t.Parallel()
+
+	enforcer := accesstypestest.NewEnforcer().
+		SetPolicy(accesstypes.Policy{
+			accesstypes.GlobalDomain: {
+				"Importer": {Allow: accesstypes.RolePermissionCollection{accesstypes.Create: {"Users"}}},
+			},
+		}).
+		AssignRoles(accesstypes.ServiceAccount("batch"), accesstypes.GlobalDomain, "Importer").
+		Grant(accesstypes.User("batch"), accesstypes.GlobalDomain, accesstypes.Create, "Users", "Users.email")
+
+	batch := ServiceAccountInfo{ServiceAccount: "batch", ID: ccc.Must(ccc.NewUUID())}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		enforcer    func(t *testing.T) accesstypes.Enforcer
+		resources   []accesstypes.Resource
+		wantOK      bool
+		wantMissing []accesstypes.Resource
+		wantSubject string
+		wantErr     bool
+	}{
+		{
+			name:        "role granted to the service account",
+			ctx:         WithServiceAccount(context.Background(), batch),
+			resources:   []accesstypes.Resource{"Users"},
+			wantOK:      true,
+			wantSubject: "service account batch",
+		},
+		{
+			name:        "grants to the user are not evaluated",
+			ctx:         WithServiceAccount(context.Background(), batch),
+			resources:   []accesstypes.Resource{"Users", "Users.email"},
+			wantMissing: []accesstypes.Resource{"Users.email"},
+			wantSubject: "service account batch",
+		},
+		{
+			name:      "service account acting as a user",
+			ctx:       WithActingAs(WithServiceAccount(context.Background(), batch), ActingAs{Impersonator: "batch", User: "customer"}),
+			resources: []accesstypes.Resource{"Users"},
+			wantErr:   true,
+		},
+		{
+			name: "enforcer without service account support",
+			ctx:  WithServiceAccount(context.Background(), batch),
+			enforcer: func(t *testing.T) accesstypes.Enforcer {
+				return mock_accesstypes.NewMockEnforcer(gomock.NewController(t))
+			},
+			resources: []accesstypes.Resource{"Users"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var e accesstypes.Enforcer = enforcer
+			if tt.enforcer != nil {
+				e = tt.enforcer(t)
+			}
+
+			ok, missing, subject, err := requireResources(tt.ctx, e, "batch", "tenant", accesstypes.Create, tt.resources...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requireResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("requireResources() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.wantMissing, missing); diff != "" {
+				t.Errorf("requireResources() missing mismatch (-want +got):\n%s", diff)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("requireResources() subject = %v, want %v", subject, tt.wantSubject)
+			}
+		})
+	}
+}
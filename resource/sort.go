@@ -0,0 +1,120 @@
+package resource
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/cccteam/httpio"
+	"github.com/go-playground/errors/v5"
+)
+
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "ASC"
+	Descending SortDirection = "DESC"
+)
+
+// SortField orders query results by Field in Direction.
+type SortField struct {
+	Field     accesstypes.Field
+	Direction SortDirection
+}
+
+// sortableFields returns the fields of Request tagged index:"true".
+func sortableFields[Request any]() map[accesstypes.Field]struct{} {
+	fields := make(map[accesstypes.Field]struct{})
+	for _, field := range reflect.VisibleFields(reflect.TypeFor[Request]()) {
+		if field.Tag.Get("index") == "true" {
+			fields[accesstypes.Field(field.Name)] = struct{}{}
+		}
+	}
+
+	return fields
+}
+
+// parseSortParam parses the sort query parameter into SortFields.
+//
+// The parameter is a comma separated list of request field json names. A leading - sorts the field
+// in descending order, for example ?sort=-createdAt,name. When sortable is not nil, only its fields may be sorted.
+func parseSortParam(fieldMapper *FieldMapper, sortable map[accesstypes.Field]struct{}, fieldMap map[accesstypes.Field]cacheEntry, queryParams url.Values) ([]SortField, error) {
+	param := queryParams.Get("sort")
+	if param == "" {
+		return nil, nil
+	}
+
+	var sortFields []SortField
+	for _, name := range strings.Split(param, ",") {
+		direction := Ascending
+		if n, found := strings.CutPrefix(name, "-"); found {
+			name = n
+			direction = Descending
+		}
+
+		field, found := fieldMapper.StructFieldName(name)
+		if !found {
+			return nil, httpio.NewBadRequestMessagef("unknown sort field: %s", name)
+		}
+
+		if _, ok := fieldMap[field]; !ok {
+			return nil, httpio.NewBadRequestMessagef("field %s can not be sorted", name)
+		}
+
+		if sortable != nil {
+			if _, ok := sortable[field]; !ok {
+				return nil, httpio.NewBadRequestMessagef("field %s can not be sorted", name)
+			}
+		}
+
+		for _, s := range sortFields {
+			if s.Field == field {
+				return nil, httpio.NewBadRequestMessagef("sort field %s is repeated", name)
+			}
+		}
+
+		sortFields = append(sortFields, SortField{Field: field, Direction: direction})
+	}
+
+	return sortFields, nil
+}
+
+// orderBy renders sortFields as a comma separated list of ordering terms for dbType.
+// NULLs sort before every other value, which is the default in Spanner and SQLite and is
+// made explicit for Postgres, so that cursors compare NULLs the same way with every dbType.
+func orderBy(dbType DBType, fieldMap map[accesstypes.Field]cacheEntry, sortFields []SortField) (string, error) {
+	terms := make([]string, 0, len(sortFields))
+	for _, s := range sortFields {
+		c, ok := fieldMap[s.Field]
+		if !ok {
+			return "", errors.Newf("field %s not found in struct", s.Field)
+		}
+
+		direction := s.Direction
+		if direction == "" {
+			direction = Ascending
+		}
+		if direction != Ascending && direction != Descending {
+			return "", errors.Newf("invalid sort direction %q", s.Direction)
+		}
+
+		switch dbType {
+		case SpannerDBType:
+			terms = append(terms, fmt.Sprintf("%s %s", c.tag, direction))
+		case PostgresDBType:
+			nulls := "NULLS FIRST"
+			if direction == Descending {
+				nulls = "NULLS LAST"
+			}
+			terms = append(terms, fmt.Sprintf(`"%s" %s %s`, c.tag, direction, nulls))
+		case SQLiteDBType:
+			terms = append(terms, fmt.Sprintf(`"%s" %s`, c.tag, direction))
+		default:
+			return "", errors.Newf("unsupported dbType: %s", dbType)
+		}
+	}
+
+	return strings.Join(terms, ", "), nil
+}
@@ -0,0 +1,127 @@
+package resource
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/cccteam/ccc/accesstypes"
+	"github.com/google/go-cmp/cmp"
+)
+
+type indexedFilterRequest struct {
+	ID        string `json:"id"        index:"true"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt" index:"true"`
+}
+
+func Test_parseSortParam(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := NewFieldMapper(filterRequest{})
+	if err != nil {
+		t.Fatalf("NewFieldMapper() error = %v", err)
+	}
+	fieldMap := structTags(reflect.TypeFor[filterResource](), string(SpannerDBType))
+
+	tests := []struct {
+		name     string
+		sortable map[accesstypes.Field]struct{}
+		query    url.Values
+		want     []SortField
+		wantErr  bool
+	}{
+		{
+			name:  "no sort",
+			query: url.Values{},
+		},
+		{
+			name:  "ascending and descending",
+			query: url.Values{"sort": {"-createdAt,status"}},
+			want: []SortField{
+				{Field: "CreatedAt", Direction: Descending},
+				{Field: "Status", Direction: Ascending},
+			},
+		},
+		{
+			name:     "restricted to indexed fields",
+			sortable: sortableFields[indexedFilterRequest](),
+			query:    url.Values{"sort": {"createdAt"}},
+			want:     []SortField{{Field: "CreatedAt", Direction: Ascending}},
+		},
+		{
+			name:     "no indexed fields",
+			sortable: sortableFields[filterRequest](),
+			query:    url.Values{"sort": {"status"}},
+			wantErr:  true,
+		},
+		{
+			name:     "field not indexed",
+			sortable: sortableFields[indexedFilterRequest](),
+			query:    url.Values{"sort": {"status"}},
+			wantErr:  true,
+		},
+		{name: "unknown field", query: url.Values{"sort": {"nope"}}, wantErr: true},
+		{name: "field without column", query: url.Values{"sort": {"hidden"}}, wantErr: true},
+		{name: "repeated field", query: url.Values{"sort": {"status,-status"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSortParam(mapper, tt.sortable, fieldMap, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSortParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseSortParam() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_OrderByClause(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dbType  DBType
+		sort    []SortField
+		want    string
+		wantErr bool
+	}{
+		{name: "none", dbType: SpannerDBType},
+		{
+			name:   "spanner",
+			dbType: SpannerDBType,
+			sort:   []SortField{{Field: "CreatedAt", Direction: Descending}, {Field: "Status"}},
+			want:   "ORDER BY CreatedAt DESC, Status ASC",
+		},
+		{
+			name:   "postgres",
+			dbType: PostgresDBType,
+			sort:   []SortField{{Field: "CreatedAt", Direction: Descending}, {Field: "Status"}},
+			want:   `ORDER BY "created_at" DESC NULLS LAST, "status" ASC NULLS FIRST`,
+		},
+		{name: "unknown field", dbType: SpannerDBType, sort: []SortField{{Field: "Hidden"}}, wantErr: true},
+		{name: "invalid direction", dbType: SpannerDBType, sort: []SortField{{Field: "Status", Direction: "SIDEWAYS"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := NewQuerySet(&ResourceMetadata[filterResource]{
+				fieldMap: structTags(reflect.TypeFor[filterResource](), string(tt.dbType)),
+				dbType:   tt.dbType,
+			}).OrderBy(tt.sort...)
+
+			got, err := q.OrderByClause()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuerySet.OrderByClause() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("QuerySet.OrderByClause() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package resource
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// SQLiteQuerier runs a query against SQLite. It is satisfied by *sql.DB, *sql.Conn and *sql.Tx
+// opened with any database/sql SQLite driver.
+type SQLiteQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// SQLiteTx runs statements inside a SQLite transaction. It is satisfied by *sql.Tx.
+type SQLiteTx interface {
+	SQLiteQuerier
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// sqliteQuery executes stmt using q, rewriting the @name parameters to numbered ?n placeholders.
+func sqliteQuery(ctx context.Context, q SQLiteQuerier, stmt Statement) (*sql.Rows, error) {
+	query, args, err := positionalStatement(stmt, "?%d")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "SQLiteQuerier.QueryContext()")
+	}
+
+	return rows, nil
+}
+
+// sqliteGet scans the first row into dst, a pointer to a struct, and reports whether a row was found.
+func sqliteGet(rows *sql.Rows, dst any) (found bool, err error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return false, errors.Wrap(err, "sql.Rows.Columns()")
+	}
+
+	return scanGet(rows, columns, SQLiteDBType, dst)
+}
+
+// sqliteSelect scans all rows into dst, a pointer to a slice of structs or struct pointers.
+func sqliteSelect(rows *sql.Rows, dst any) error {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return errors.Wrap(err, "sql.Rows.Columns()")
+	}
+
+	return scanSelect(rows, columns, SQLiteDBType, dst)
+}
+
+// sqliteTx adapts a SQLiteTx for PatchSet writes.
+type sqliteTx struct {
+	tx SQLiteTx
+}
+
+func (sqliteTx) dbType() DBType {
+	return SQLiteDBType
+}
+
+func (t sqliteTx) get(ctx context.Context, stmt Statement, dst any) (bool, error) {
+	rows, err := sqliteQuery(ctx, t.tx, stmt)
+	if err != nil {
+		return false, err
+	}
+
+	return sqliteGet(rows, dst)
+}
+
+func (t sqliteTx) exec(ctx context.Context, stmt Statement) (int64, error) {
+	query, args, err := positionalStatement(stmt, "?%d")
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := t.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "SQLiteTx.ExecContext()")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "sql.Result.RowsAffected()")
+	}
+
+	return rowsAffected, nil
+}
+
+// Extended result codes for SQLITE_CONSTRAINT, see https://www.sqlite.org/rescode.html
+const (
+	sqliteConstraintCheck      = 275
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintNotNull    = 1299
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// constraint recognizes errors from drivers that report the SQLite extended result code
+// through a Code() int method, such as modernc.org/sqlite.
+func (sqliteTx) constraint(err error) (constraintKind, string, bool) {
+	var codeErr interface {
+		error
+		Code() int
+	}
+	if !errors.As(err, &codeErr) {
+		return 0, "", false
+	}
+
+	// SQLite reports the constraint as "... constraint failed: Table.column"
+	_, name, _ := strings.Cut(codeErr.Error(), "constraint failed: ")
+
+	switch codeErr.Code() {
+	case sqliteConstraintUnique, sqliteConstraintPrimaryKey:
+		return uniqueViolation, name, true
+	case sqliteConstraintForeignKey:
+		return foreignKeyViolation, name, true
+	case sqliteConstraintNotNull:
+		return notNullViolation, name, true
+	case sqliteConstraintCheck:
+		return checkViolation, name, true
+	default:
+		return 0, "", false
+	}
+}
+
+// sqliteQueryParser compiles a search string to SQLite LIKE predicates. It accepts the
+// same query language as spannerQueryParser, and the search key names the text column searched.
+type sqliteQueryParser struct {
+	terms searchQuery
+	// prefix namespaces the parameter names when several searches share a statement
+	prefix string
+}
+
+func parseSQLiteQuery(query string) (*sqliteQueryParser, error) {
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqliteQueryParser{terms: terms}, nil
+}
+
+// parseToSearchSubstring matches the terms anywhere in the column. LIKE is case-insensitive for ASCII in SQLite.
+func (s sqliteQueryParser) parseToSearchSubstring(column SearchKey) *Statement {
+	params := make(map[string]any)
+	expr := s.terms.render(func(t searchTerm) string {
+		param := fmt.Sprintf("%ssearchsubstringterm%d", s.prefix, t.index)
+		params[param] = "%" + escapeLike(t.text) + "%"
+
+		return fmt.Sprintf(`"%s" LIKE @%s ESCAPE '\'`, column, param)
+	})
+
+	return &Statement{
+		Sql:    expr,
+		Params: params,
+	}
+}
+
+// parseToSubstringScore counts the terms which match.
+func (s sqliteQueryParser) parseToSubstringScore(column SearchKey) *Statement {
+	return s.terms.sum(func(t searchTerm) (string, string, any) {
+		param := fmt.Sprintf("%ssearchsubstringterm%d", s.prefix, t.index)
+
+		return fmt.Sprintf(`(CASE WHEN "%s" LIKE @%s ESCAPE '\' THEN 1 ELSE 0 END)`, column, param), param, "%" + escapeLike(t.text) + "%"
+	})
+}
@@ -0,0 +1,297 @@
+package resource
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE FilterResources (
+	"id" TEXT PRIMARY KEY,
+	"status" TEXT NOT NULL UNIQUE,
+	"count" INTEGER NOT NULL DEFAULT 0,
+	"created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"deleted_at" TIMESTAMP
+);
+CREATE TABLE DataChangeEvents (
+	"TableName" TEXT NOT NULL,
+	"RowId" TEXT NOT NULL,
+	"EventTime" TIMESTAMP NOT NULL,
+	"EventSource" TEXT NOT NULL,
+	"ChangeSet" TEXT NOT NULL
+);
+INSERT INTO FilterResources ("id", "status", "count") VALUES
+	('a', 'active', 1),
+	('b', 'pending review', 2),
+	('c', '50% done', 3);
+`
+
+// newSQLiteDB opens a private in-memory database loaded with sqliteSchema.
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	// every connection to :memory: is a new database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		t.Fatalf("sql.DB.Exec() error = %v", err)
+	}
+
+	return db
+}
+
+func newSQLitePatchSet(trackChanges bool) *PatchSet[filterResource] {
+	return NewPatchSet(&ResourceMetadata[filterResource]{
+		fieldMap:            structTags(reflect.TypeFor[filterResource](), string(SQLiteDBType)),
+		primaryKeys:         primaryKeyFields(reflect.TypeFor[filterResource]()),
+		dbType:              SQLiteDBType,
+		changeTrackingTable: "DataChangeEvents",
+		trackChanges:        trackChanges,
+	})
+}
+
+func TestQuerySet_SQLiteRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		key          string
+		want         filterResource
+		wantNotFound bool
+	}{
+		{
+			name: "found",
+			key:  "b",
+			want: filterResource{ID: "b", Status: "pending review", Count: 2},
+		},
+		{
+			name:         "not found",
+			key:          "z",
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := newSQLiteDB(t)
+			q := newFilterQuerySet(SQLiteDBType).AddField("ID").AddField("Status").AddField("Count")
+			q.SetKey("ID", tt.key)
+
+			var got filterResource
+			err := q.SQLiteRead(context.Background(), db, &got)
+			if tt.wantNotFound {
+				if err == nil || !strings.Contains(err.Error(), "FilterResources (ID: z) not found") {
+					t.Fatalf("QuerySet.SQLiteRead() error = %v, want not found", err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("QuerySet.SQLiteRead() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuerySet.SQLiteRead() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_SQLiteList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filters []Filter
+		search  *SearchSet
+		want    []string
+	}{
+		{
+			name:    "in",
+			filters: []Filter{{Field: "Status", Operator: InOperator, Value: []string{"active", "50% done"}}},
+			want:    []string{"a", "c"},
+		},
+		{
+			name:    "prefix",
+			filters: []Filter{{Field: "Status", Operator: PrefixOperator, Value: "pend"}},
+			want:    []string{"b"},
+		},
+		{
+			name:    "greater than",
+			filters: []Filter{{Field: "Count", Operator: GreaterThanOperator, Value: 1}},
+			want:    []string{"b", "c"},
+		},
+		{
+			name:   "search substring",
+			search: NewSearchSet(SubString, "status", "REVIEW"),
+			want:   []string{"b"},
+		},
+		{
+			name:   "search escapes wildcards",
+			search: NewSearchSet(SubString, "status", "50%"),
+			want:   []string{"c"},
+		},
+		{
+			name:   "search ranks by matching terms",
+			search: NewSearchSet(SubString, "status", "done active 50"),
+			want:   []string{"c", "a"},
+		},
+		{
+			name:   "search excluded term",
+			search: NewSearchSet(SubString, "status", "e -review"),
+			want:   []string{"a", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := newSQLiteDB(t)
+			q := newFilterQuerySet(SQLiteDBType).AddField("ID").AddField("Status")
+			for _, f := range tt.filters {
+				q.AddFilter(f)
+			}
+			if tt.search != nil {
+				q.SetSearchParam(tt.search)
+			} else {
+				q.OrderBy(SortField{Field: "ID", Direction: Ascending})
+			}
+
+			var got []*filterResource
+			if err := q.SQLiteList(context.Background(), db, &got); err != nil {
+				t.Fatalf("QuerySet.SQLiteList() error = %v", err)
+			}
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			if diff := cmp.Diff(tt.want, ids); diff != "" {
+				t.Errorf("QuerySet.SQLiteList() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPatchSet_SQLiteApply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		patchType  PatchType
+		key        string
+		status     string
+		wantRow    *filterResource
+		wantEvents int
+		wantErr    string
+	}{
+		{
+			name:       "create",
+			patchType:  CreatePatchType,
+			key:        "d",
+			status:     "draft",
+			wantRow:    &filterResource{ID: "d", Status: "draft"},
+			wantEvents: 1,
+		},
+		{
+			name:       "update",
+			patchType:  UpdatePatchType,
+			key:        "a",
+			status:     "closed",
+			wantRow:    &filterResource{ID: "a", Status: "closed"},
+			wantEvents: 1,
+		},
+		{
+			name:       "delete",
+			patchType:  DeletePatchType,
+			key:        "a",
+			wantEvents: 1,
+		},
+		{
+			name:      "create duplicate key",
+			patchType: CreatePatchType,
+			key:       "a",
+			status:    "draft",
+			wantErr:   "FilterResources (ID: a) already exists",
+		},
+		{
+			name:      "update unique violation",
+			patchType: UpdatePatchType,
+			key:       "a",
+			status:    "pending review",
+			wantErr:   "FilterResources (ID: a) already exists",
+		},
+		{
+			name:      "update not found",
+			patchType: UpdatePatchType,
+			key:       "z",
+			status:    "closed",
+			wantErr:   "FilterResources (ID: z) not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			db := newSQLiteDB(t)
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatalf("sql.DB.BeginTx() error = %v", err)
+			}
+			defer func() { _ = tx.Rollback() }()
+
+			p := newSQLitePatchSet(true).SetPatchType(tt.patchType).SetKey("ID", tt.key)
+			if tt.status != "" {
+				p.Set("Status", tt.status)
+			}
+
+			err = p.SQLiteApply(ctx, tx, "test")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PatchSet.SQLiteApply() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("PatchSet.SQLiteApply() error = %v", err)
+			}
+
+			q := newFilterQuerySet(SQLiteDBType).AddField("ID").AddField("Status")
+			q.SetKey("ID", tt.key)
+			var got filterResource
+			err = q.SQLiteRead(ctx, tx, &got)
+			switch {
+			case tt.wantRow == nil && err == nil:
+				t.Errorf("QuerySet.SQLiteRead() found %v, want deleted", got)
+			case tt.wantRow != nil && err != nil:
+				t.Errorf("QuerySet.SQLiteRead() error = %v", err)
+			case tt.wantRow != nil:
+				if diff := cmp.Diff(*tt.wantRow, got); diff != "" {
+					t.Errorf("QuerySet.SQLiteRead() mismatch (-want +got):\n%s", diff)
+				}
+			}
+
+			var events int
+			if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM DataChangeEvents WHERE "RowId" = ?`, tt.key).Scan(&events); err != nil {
+				t.Fatalf("sql.Tx.QueryRowContext() error = %v", err)
+			}
+			if events != tt.wantEvents {
+				t.Errorf("DataChangeEvents = %d, want %d", events, tt.wantEvents)
+			}
+		})
+	}
+}
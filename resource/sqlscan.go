@@ -0,0 +1,113 @@
+package resource
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// rowScanner iterates over the rows of a query result. It is satisfied by pgx.Rows and *sql.Rows.
+type rowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// scanGet scans the first row into dst, a pointer to a struct, and reports whether a row was found.
+// Columns are matched to fields using the struct tag named after dbType.
+func scanGet(rows rowScanner, columns []string, dbType DBType, dst any) (found bool, err error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return false, errors.Newf("dst must be a pointer to a struct, got %T", dst)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, errors.Wrap(err, "rows.Err()")
+		}
+
+		return false, nil
+	}
+
+	if err := scanRow(rows, columns, dbType, v.Elem()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// scanSelect scans all rows into dst, a pointer to a slice of structs or struct pointers.
+// Columns are matched to fields using the struct tag named after dbType.
+func scanSelect(rows rowScanner, columns []string, dbType DBType, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return errors.Newf("dst must be a pointer to a slice, got %T", dst)
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.Newf("dst must be a pointer to a slice of structs, got %T", dst)
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := scanRow(rows, columns, dbType, elem.Elem()); err != nil {
+			return err
+		}
+
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "rows.Err()")
+	}
+
+	return nil
+}
+
+// scanRow scans the current row into the fields of v matched by their dbType struct tag.
+func scanRow(rows rowScanner, columns []string, dbType DBType, v reflect.Value) error {
+	fields := taggedFields(v.Type(), dbType)
+
+	dests := make([]any, 0, len(columns))
+	for _, column := range columns {
+		index, ok := fields[column]
+		if !ok {
+			return errors.Newf("column %s has no matching %s tag in %s", column, dbType, v.Type())
+		}
+		dests = append(dests, v.FieldByIndex(index).Addr().Interface())
+	}
+
+	if err := rows.Scan(dests...); err != nil {
+		return errors.Wrap(err, "rows.Scan()")
+	}
+
+	return nil
+}
+
+// taggedFields maps the dbType struct tags of t to the index of their field.
+func taggedFields(t reflect.Type, dbType DBType) map[string][]int {
+	fields := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(field.Tag.Get(string(dbType)), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		fields[tag] = field.Index
+	}
+
+	return fields
+}
@@ -0,0 +1,103 @@
+package resource
+
+import (
+	"net/url"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/cccteam/httpio"
+)
+
+// SetStrongRead reads the most recent data with Spanner, which is the default.
+func (q *QuerySet[Resource]) SetStrongRead() *QuerySet[Resource] {
+	return q.setTimestampBound(spanner.StrongRead(), false)
+}
+
+// SetMaxStaleness allows Spanner reads to return data up to d old, which can be served by the nearest replica
+// without waiting for the leader.
+func (q *QuerySet[Resource]) SetMaxStaleness(d time.Duration) *QuerySet[Resource] {
+	return q.setTimestampBound(spanner.MaxStaleness(d), true)
+}
+
+// SetReadTimestamp reads the data as of t with Spanner, which must be within the database's version retention period.
+func (q *QuerySet[Resource]) SetReadTimestamp(t time.Time) *QuerySet[Resource] {
+	return q.setTimestampBound(spanner.ReadTimestamp(t), false)
+}
+
+// setTimestampBound sets the timestamp bound of the transaction read by SpannerRead, SpannerList,
+// SpannerListPage, SpannerBatchRead and SpannerAggregate, which must be created with SpannerReadTransaction.
+func (q *QuerySet[Resource]) setTimestampBound(bound spanner.TimestampBound, maxStaleness bool) *QuerySet[Resource] {
+	q.timestampBound = &bound
+	q.maxStaleness = maxStaleness
+
+	return q
+}
+
+// TimestampBound returns the timestamp bound set with SetStrongRead, SetMaxStaleness or SetReadTimestamp,
+// or nil when the transaction's own bound is used.
+func (q *QuerySet[Resource]) TimestampBound() *spanner.TimestampBound {
+	return q.timestampBound
+}
+
+// SpannerReadTransaction returns a read-only transaction on client with the timestamp bound of q, which can only be
+// set when a transaction is created. Bounded staleness is only supported by single-use transactions, which run a
+// single query, so it can not be combined with includes or totals. The caller must Close the transaction.
+func SpannerReadTransaction[Resource Resourcer](client *spanner.Client, q *QuerySet[Resource]) *spanner.ReadOnlyTransaction {
+	switch {
+	case q.timestampBound == nil:
+		return client.ReadOnlyTransaction()
+	case q.maxStaleness:
+		return client.Single().WithTimestampBound(*q.timestampBound)
+	default:
+		return client.ReadOnlyTransaction().WithTimestampBound(*q.timestampBound)
+	}
+}
+
+// checkTimestampBound rejects a max staleness bound on queries which run more than one query.
+func (q *QuerySet[Resource]) checkTimestampBound() error {
+	if q.maxStaleness && (len(q.includes) > 0 || q.total) {
+		return httpio.NewBadRequestMessage("maxStaleness can not be combined with include or total")
+	}
+
+	return nil
+}
+
+// timestampBoundParam is the timestamp bound requested with the asOf or maxStaleness query parameters.
+type timestampBoundParam struct {
+	asOf         time.Time
+	maxStaleness time.Duration
+}
+
+// parseTimestampBoundParams parses the asOf and maxStaleness query parameters. asOf reads the data as of an
+// RFC 3339 timestamp in the past, and maxStaleness allows data up to a duration old, for example:
+//
+//	?asOf=2024-06-01T12:00:00Z
+//	?maxStaleness=15s
+func parseTimestampBoundParams(queryParams url.Values, now time.Time) (*timestampBoundParam, error) {
+	asOf, staleness := queryParams.Get("asOf"), queryParams.Get("maxStaleness")
+	if asOf != "" && staleness != "" {
+		return nil, httpio.NewBadRequestMessage("asOf and maxStaleness can not be combined")
+	}
+
+	switch {
+	case asOf != "":
+		t, err := time.Parse(time.RFC3339Nano, asOf)
+		if err != nil {
+			return nil, httpio.NewBadRequestMessagef("invalid asOf timestamp %q, expected RFC 3339", asOf)
+		}
+		if t.After(now) {
+			return nil, httpio.NewBadRequestMessagef("asOf timestamp %s is in the future", asOf)
+		}
+
+		return &timestampBoundParam{asOf: t}, nil
+	case staleness != "":
+		d, err := time.ParseDuration(staleness)
+		if err != nil || d <= 0 {
+			return nil, httpio.NewBadRequestMessagef("invalid maxStaleness %q, expected a positive duration", staleness)
+		}
+
+		return &timestampBoundParam{maxStaleness: d}, nil
+	}
+
+	return nil, nil
+}
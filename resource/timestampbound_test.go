@@ -0,0 +1,134 @@
+package resource
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseTimestampBoundParams(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    *timestampBoundParam
+		wantErr bool
+	}{
+		{name: "no bound", query: url.Values{}},
+		{
+			name:  "as of",
+			query: url.Values{"asOf": {"2024-06-01T11:00:00.5Z"}},
+			want:  &timestampBoundParam{asOf: time.Date(2024, 6, 1, 11, 0, 0, 5e8, time.UTC)},
+		},
+		{
+			name:  "max staleness",
+			query: url.Values{"maxStaleness": {"15s"}},
+			want:  &timestampBoundParam{maxStaleness: 15 * time.Second},
+		},
+		{name: "as of in the future", query: url.Values{"asOf": {"2024-06-01T13:00:00Z"}}, wantErr: true},
+		{name: "invalid as of", query: url.Values{"asOf": {"yesterday"}}, wantErr: true},
+		{name: "invalid max staleness", query: url.Values{"maxStaleness": {"-1s"}}, wantErr: true},
+		{name: "combined", query: url.Values{"asOf": {"2024-06-01T11:00:00Z"}, "maxStaleness": {"15s"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseTimestampBoundParams(tt.query, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTimestampBoundParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(timestampBoundParam{})); diff != "" {
+				t.Errorf("parseTimestampBoundParams() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuerySet_TimestampBound(t *testing.T) {
+	t.Parallel()
+
+	asOf := time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		prepare          func(q *QuerySet[filterResource])
+		want             *spanner.TimestampBound
+		wantMaxStaleness bool
+	}{
+		{name: "transaction bound", prepare: func(*QuerySet[filterResource]) {}},
+		{
+			name:    "strong",
+			prepare: func(q *QuerySet[filterResource]) { q.SetMaxStaleness(time.Minute).SetStrongRead() },
+			want:    ptr(spanner.StrongRead()),
+		},
+		{
+			name:             "max staleness",
+			prepare:          func(q *QuerySet[filterResource]) { q.SetMaxStaleness(15 * time.Second) },
+			want:             ptr(spanner.MaxStaleness(15 * time.Second)),
+			wantMaxStaleness: true,
+		},
+		{
+			name:    "read timestamp",
+			prepare: func(q *QuerySet[filterResource]) { q.SetReadTimestamp(asOf) },
+			want:    ptr(spanner.ReadTimestamp(asOf)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(SpannerDBType)
+			tt.prepare(q)
+
+			got := q.TimestampBound()
+			if (got == nil) != (tt.want == nil) || (got != nil && got.String() != tt.want.String()) {
+				t.Errorf("QuerySet.TimestampBound() = %v, want %v", got, tt.want)
+			}
+			if q.maxStaleness != tt.wantMaxStaleness {
+				t.Errorf("QuerySet.maxStaleness = %v, want %v", q.maxStaleness, tt.wantMaxStaleness)
+			}
+		})
+	}
+}
+
+func TestQuerySet_checkTimestampBound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		prepare func(q *QuerySet[filterResource])
+		wantErr bool
+	}{
+		{name: "no bound", prepare: func(q *QuerySet[filterResource]) { q.SetIncludeTotal(true) }},
+		{name: "max staleness", prepare: func(q *QuerySet[filterResource]) { q.SetMaxStaleness(15 * time.Second) }},
+		{name: "read timestamp with total", prepare: func(q *QuerySet[filterResource]) { q.SetReadTimestamp(time.Now()).SetIncludeTotal(true) }},
+		{
+			name:    "max staleness with total",
+			prepare: func(q *QuerySet[filterResource]) { q.SetMaxStaleness(15 * time.Second).SetIncludeTotal(true) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newFilterQuerySet(SpannerDBType)
+			tt.prepare(q)
+
+			if err := q.checkTimestampBound(); (err != nil) != tt.wantErr {
+				t.Errorf("QuerySet.checkTimestampBound() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -26,9 +26,12 @@ type cacheEntry struct {
 }
 
 type TypescriptData struct {
-	Permissions         []accesstypes.Permission
-	Resources           []accesstypes.Resource
-	ResourceTags        map[accesstypes.Resource][]accesstypes.Tag
-	ResourcePermissions permissionMap
-	Domains             []accesstypes.PermissionScope
+	Permissions            []accesstypes.Permission
+	PermissionKinds        map[accesstypes.Permission]accesstypes.PermissionKind
+	Resources              []accesstypes.Resource
+	ResourceTags           map[accesstypes.Resource][]accesstypes.Tag
+	ResourcePermissions    permissionMap
+	Domains                []accesstypes.PermissionScope
+	PermissionDescriptions map[accesstypes.Permission]string
+	ResourceDescriptions   map[accesstypes.Resource]string
 }